@@ -2,10 +2,14 @@ package v1alpha1
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"strings"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,6 +35,188 @@ type ObjectSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
 	ForProvider       ObjectParameters `json:"forProvider"`
 	Readiness         Readiness        `json:"readiness,omitempty"`
+	// `updateStrategy` defines how the remote object is created, updated and checked for drift.
+	// +optional
+	// +kubebuilder:validation:Enum=ServerSideApply;Replace;MergePatch;CreateOnly
+	// +kubebuilder:default=ServerSideApply
+	UpdateStrategy UpdateStrategy `json:"updateStrategy,omitempty"`
+	// `apply` defines how the manifest is server-side applied to the remote cluster.
+	// Only `fieldManager` is used with update strategies other than ServerSideApply.
+	// +optional
+	Apply Apply `json:"apply,omitempty"`
+	// `drift` defines how the drift of the remote object from the manifest is detected.
+	// +optional
+	Drift Drift `json:"drift,omitempty"`
+	// `deletion` defines how the remote object is deleted.
+	// +optional
+	Deletion Deletion `json:"deletion,omitempty"`
+	// `references` inject values read from other objects into the manifest before it's applied.
+	// The remote object is not created until all of them are resolved.
+	// +optional
+	References []Reference `json:"references,omitempty"`
+	// `connectionDetails` are published to `writeConnectionSecretToRef` or `publishConnectionDetailsTo`.
+	// Their values are read from the observed remote object.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ConnectionDetails []ConnectionDetail `json:"connectionDetails,omitempty"`
+	// `statusProjections` is a map of names to CEL expressions evaluated against the observed remote object, the same way as the readiness `celExpression`.
+	// Their results are written to `status.atProvider.fields` under the same names. Expressions that fail to evaluate, e.g. due to missing fields, are skipped.
+	// +optional
+	StatusProjections map[string]string `json:"statusProjections,omitempty"`
+}
+
+// ConnectionDetail publishes a value read from the observed remote object as a connection detail.
+// +kubebuilder:validation:XValidation:rule="has(self.fieldPath) != has(self.celExpression)",message="exactly one of fieldPath and celExpression must be set"
+type ConnectionDetail struct {
+	// `name` of the connection detail, e.g. the key in the connection secret.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// `fieldPath` of the value in the observed remote object, e.g. `status.loadBalancer.ingress[0].ip`.
+	// Values read from the `data` field of a Secret are base64-decoded. Missing fields are skipped.
+	// +optional
+	FieldPath string `json:"fieldPath,omitempty"`
+	// `celExpression` computes the value from the observed remote object, the same way as the readiness `celExpression`.
+	// +optional
+	CELExpression string `json:"celExpression,omitempty"`
+}
+
+// Reference injects a value read from another object into `spec.forProvider.manifest`.
+// +kubebuilder:validation:XValidation:rule="(has(self.object) ? 1 : 0) + (has(self.configMapKeyRef) ? 1 : 0) + (has(self.secretKeyRef) ? 1 : 0) == 1",message="exactly one of object, configMapKeyRef and secretKeyRef must be set"
+type Reference struct {
+	// `object` reads the value from another Object's `status.atProvider.manifest`.
+	// +optional
+	Object *ObjectFieldSelector `json:"object,omitempty"`
+	// `configMapKeyRef` reads the value from a key of a ConfigMap in the local cluster.
+	// +optional
+	ConfigMapKeyRef *KeySelector `json:"configMapKeyRef,omitempty"`
+	// `secretKeyRef` reads the value from a key of a Secret in the local cluster.
+	// +optional
+	SecretKeyRef *KeySelector `json:"secretKeyRef,omitempty"`
+	// `toFieldPath` is the path in `spec.forProvider.manifest` the value is written to, e.g. `spec.template.spec.containers[0].env[0].value`.
+	// +kubebuilder:validation:MinLength=1
+	ToFieldPath string `json:"toFieldPath"`
+}
+
+// ObjectFieldSelector selects a field of another Object's observed remote object.
+type ObjectFieldSelector struct {
+	// `name` of the referenced Object.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// `fieldPath` in the referenced Object's `status.atProvider.manifest`, e.g. `spec.clusterIP`.
+	// +kubebuilder:validation:MinLength=1
+	FieldPath string `json:"fieldPath"`
+}
+
+// KeySelector selects a key of a namespaced object in the local cluster.
+type KeySelector struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// UpdateStrategy defines how the remote object is created, updated and checked for drift.
+type UpdateStrategy string
+
+const (
+	// UpdateStrategyServerSideApply means the manifest is server-side applied, both to create and to update the remote object.
+	UpdateStrategyServerSideApply UpdateStrategy = "ServerSideApply"
+	// UpdateStrategyReplace means the remote object is created and then replaced with the manifest, using the resourceVersion
+	// of the current remote object to avoid overwriting concurrent changes.
+	UpdateStrategyReplace UpdateStrategy = "Replace"
+	// UpdateStrategyMergePatch means the remote object is created and then updated with the manifest sent as a JSON merge patch.
+	UpdateStrategyMergePatch UpdateStrategy = "MergePatch"
+	// UpdateStrategyCreateOnly means the remote object is created, but never updated nor checked for drift afterwards.
+	UpdateStrategyCreateOnly UpdateStrategy = "CreateOnly"
+)
+
+// Apply defines how the manifest is server-side applied to the remote cluster.
+type Apply struct {
+	// `fieldManager` is the name of the field manager owning the applied fields. Defaults to `provider-k8s`.
+	// Fields owned by the previous field manager are left intact after it's changed.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	FieldManager string `json:"fieldManager,omitempty"`
+	// `force` takes over the ownership of fields managed by other field managers, e.g. a HorizontalPodAutoscaler or kubectl.
+	// If it's disabled, conflicts with other field managers are reported in the Synced condition instead. Defaults to true.
+	// +optional
+	Force *bool `json:"force,omitempty"`
+	// `forceFields` lists the field paths, e.g. `spec.replicas`, whose ownership is taken over even if `force` is disabled.
+	// Fields nested under a listed path are forced as well.
+	// +optional
+	ForceFields []string `json:"forceFields,omitempty"`
+}
+
+// Drift defines how the drift of the remote object from the manifest is detected.
+type Drift struct {
+	// `ignore` lists the fields whose differences are not considered a drift, e.g. `spec.replicas` of a Deployment scaled
+	// by a HorizontalPodAutoscaler. Note that the ignored fields are still applied when the remote object is updated
+	// due to other differences, so they usually should be removed from the manifest as well.
+	// +optional
+	Ignore DriftIgnore `json:"ignore,omitempty"`
+}
+
+// DriftIgnore lists the fields whose differences are not considered a drift.
+type DriftIgnore struct {
+	// `jsonPointers` are RFC 6901 JSON pointers, e.g. `/spec/replicas` or `/metadata/annotations/example.com~1revision`.
+	// +optional
+	JSONPointers []string `json:"jsonPointers,omitempty"`
+	// `fieldPaths` are field paths that may contain `*` wildcards, e.g. `spec.template.spec.containers[*].image`.
+	// +optional
+	FieldPaths []string `json:"fieldPaths,omitempty"`
+	// `managedFieldsManagers` ignores the fields owned by the given field managers of the remote object, e.g. `kube-controller-manager`.
+	// +optional
+	ManagedFieldsManagers []string `json:"managedFieldsManagers,omitempty"`
+}
+
+// Deletion defines how the remote object should be deleted.
+type Deletion struct {
+	// `propagationPolicy` defines whether and how the garbage collector deletes the dependents of the remote object.
+	// Defaults to the remote API server's default for the object's kind.
+	// +optional
+	// +kubebuilder:validation:Enum=Foreground;Background;Orphan
+	PropagationPolicy *metav1.DeletionPropagation `json:"propagationPolicy,omitempty"`
+	// `gracePeriodSeconds` is the duration in seconds before the remote object should be deleted.
+	// Defaults to the remote API server's default for the object's kind.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+	// `waitUntilGone` keeps the Object's finalizer until the remote object is really gone, e.g. until the pods of a Deployment
+	// deleted with the Foreground propagation policy or the finalizers of a Namespace are done.
+	// By default the Object is removed as soon as the deletion of the remote object is requested.
+	// +optional
+	WaitUntilGone bool `json:"waitUntilGone,omitempty"`
+	// `timeout` is the maximum time the Object waits for the remote object to be gone, measured from the Object's deletion.
+	// After that the Object's finalizer is removed even if the remote object still exists. There's no timeout by default.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// Object's condition types and reasons.
+const (
+	// TypeRemoteDeletion conditions report the progress of the remote object's deletion.
+	TypeRemoteDeletion xpv1.ConditionType = "RemoteDeletion"
+
+	ReasonWaitingForRemoteDeletion xpv1.ConditionReason = "WaitingForRemoteDeletion"
+)
+
+// WaitingForRemoteDeletion returns a condition that indicates the remote object is still being deleted
+// due to the given finalizers.
+func WaitingForRemoteDeletion(finalizers []string) xpv1.Condition {
+	msg := "Remote object is being deleted"
+	if len(finalizers) > 0 {
+		msg = fmt.Sprintf("Remote object is being deleted, blocked by finalizers: %s", strings.Join(finalizers, ", "))
+	}
+	return xpv1.Condition{
+		Type:               TypeRemoteDeletion,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWaitingForRemoteDeletion,
+		Message:            msg,
+	}
 }
 
 type StatusWithObservedGeneration struct {
@@ -50,6 +236,23 @@ type ObjectObservation struct {
 	// +kubebuilder:validation:EmbeddedResource
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Manifest runtime.RawExtension `json:"manifest,omitempty"`
+	// `fields` are the results of `spec.statusProjections`.
+	// +optional
+	Fields map[string]extv1.JSON `json:"fields,omitempty"`
+	// `lastDrift` is the last drift of the remote object from the manifest that has been corrected.
+	// +optional
+	LastDrift *CorrectedDrift `json:"lastDrift,omitempty"`
+	// `host` is the URL of the API server of the remote cluster.
+	// +optional
+	Host string `json:"host,omitempty"`
+}
+
+// CorrectedDrift is a drift of the remote object from the manifest that has been corrected.
+type CorrectedDrift struct {
+	// `time` when the drift has been corrected.
+	Time metav1.Time `json:"time"`
+	// `diff` from the remote object to the manifest, as a JSON patch followed by a YAML diff. It may be truncated.
+	Diff string `json:"diff"`
 }
 
 // ReadinessPolicy defines how the Object's readiness condition should be computed.
@@ -66,20 +269,72 @@ const (
 	ReadinessPolicyDeriveFromObject ReadinessPolicy = "DeriveFromObject"
 	// ReadinessPolicyUseCELExpression means the object is marked ready if the celExpression returns true.
 	ReadinessPolicyUseCELExpression ReadinessPolicy = "UseCELExpression"
+	// ReadinessPolicyMatchConditions means the object is marked ready if its `status.conditions` match the listed ones,
+	// e.g. `Available` for Deployments, `Complete` for Jobs or `Established` for CustomResourceDefinitions.
+	ReadinessPolicyMatchConditions ReadinessPolicy = "MatchConditions"
+	// ReadinessPolicyAuto means the object is marked ready according to built-in health rules for well-known kinds,
+	// e.g. a rolled out Deployment, a complete Job or a LoadBalancer Service with an ingress assigned.
+	// Other kinds fall back to `status.observedGeneration` and the `Ready` condition, if they report them.
+	ReadinessPolicyAuto ReadinessPolicy = "Auto"
 )
 
+// ConditionsMatch defines how many of the listed conditions must match for the object to be ready.
+type ConditionsMatch string
+
+const (
+	// ConditionsMatchAll means all listed conditions must match.
+	ConditionsMatchAll ConditionsMatch = "All"
+	// ConditionsMatchAny means at least one of the listed conditions must match.
+	ConditionsMatchAny ConditionsMatch = "Any"
+)
+
+// ReadinessCondition is a condition the observed object must report to be ready.
+type ReadinessCondition struct {
+	// `type` of the condition, e.g. `Available`.
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+	// `status` the condition must have.
+	// +optional
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	// +kubebuilder:default=True
+	Status corev1.ConditionStatus `json:"status,omitempty"`
+}
+
 // Readiness defines how the object's readiness condition should be computed,
 // if not specified it will be considered ready as soon as the underlying external
 // resource is considered up-to-date.
 // +kubebuilder:validation:XValidation:rule="self.policy == 'UseCELExpression' ? has(self.celExpression) : !has(self.celExpression)",message="celExpression should be set only if policy is equal to UseCELExpression"
+// +kubebuilder:validation:XValidation:rule="self.policy == 'MatchConditions' ? has(self.conditions) : !has(self.conditions)",message="conditions should be set only if policy is equal to MatchConditions"
 type Readiness struct {
 	// `policy` defines how the Object's readiness condition should be computed.
 	// +optional
-	// +kubebuilder:validation:Enum=SuccessfulCreate;DeriveFromObject;UseCELExpression
+	// +kubebuilder:validation:Enum=SuccessfulCreate;DeriveFromObject;UseCELExpression;MatchConditions;Auto
 	// +kubebuilder:default=SuccessfulCreate
 	Policy ReadinessPolicy `json:"policy,omitempty"`
-	// `celExpression` defines the CEL expression that should be executed to compute whether the Object is ready. It must return boolean value. See docs for examples.
+	// `celExpression` defines the CEL expression that should be executed to compute whether the Object is ready. It must return
+	// a boolean value, a `{"ready": bool, "reason": string, "message": string}` map with optional reason and message,
+	// or a list of strings describing the failed checks, which means the Object is ready if the list is empty.
+	// The reason and message are copied to the Ready condition. The observed object's top-level fields are available as
+	// untyped variables, e.g. `status`, and the whole object as `self`, typed with the remote cluster's OpenAPI schema of
+	// its kind, if it's published, so that typos are reported when the expression is compiled. See docs for examples.
 	CELExpression string `json:"celExpression,omitempty"`
+	// `celCostLimit` overrides the provider's default limit of the runtime cost of `celExpression`, which roughly corresponds
+	// to the number of operations the expression performs. The object is not ready if the expression exceeds it.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	CELCostLimit *int64 `json:"celCostLimit,omitempty"`
+	// `conditions` the observed object must report to be ready, used with the MatchConditions policy.
+	// +optional
+	// +kubebuilder:validation:MinItems=1
+	Conditions []ReadinessCondition `json:"conditions,omitempty"`
+	// `conditionsMatch` defines whether all or any of the `conditions` must match. Defaults to All.
+	// +optional
+	// +kubebuilder:validation:Enum=All;Any
+	ConditionsMatch ConditionsMatch `json:"conditionsMatch,omitempty"`
+	// `checkObservedGeneration` additionally requires the observed object's `status.observedGeneration`, if it's reported,
+	// to be equal to its `metadata.generation`, used with the MatchConditions policy.
+	// +optional
+	CheckObservedGeneration bool `json:"checkObservedGeneration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,132 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"aerf.io/provider-k8s/internal/controllers/generic"
+)
+
+// ObjectSetManifest is a single kubernetes object managed as a part of an ObjectSet.
+type ObjectSetManifest struct {
+	// Raw YAML representation of the kubernetes object to be created.
+	// +kubebuilder:validation:EmbeddedResource
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:XValidation:rule="!(has(self.metadata.generateName))",message="generateName is disallowed"
+	Manifest runtime.RawExtension `json:"manifest"`
+	// `wave` explicitly orders the manifests. Manifests from lower waves are applied first and the next wave is applied only
+	// after every manifest from the previous one is ready. On deletion the order is reversed.
+	// Within a single wave the manifests are ordered by their kind, e.g. Namespaces and CustomResourceDefinitions go first,
+	// and the rest of the wave is applied only once they're active and established, respectively. On deletion they're
+	// deleted only after the rest of the wave is gone.
+	// +optional
+	Wave int32 `json:"wave,omitempty"`
+	// `readiness` defines how the readiness of this manifest should be computed.
+	// +optional
+	Readiness Readiness `json:"readiness,omitempty"`
+}
+
+// ObjectSetParameters are the configurable fields of a ObjectSet.
+type ObjectSetParameters struct {
+	// `manifests` is a list of kubernetes objects applied as one unit. Manifests removed from this list are not
+	// deleted from the remote cluster. The ObjectSet is not reconciled while one of its remote objects is managed by an
+	// older Object or ObjectSet, and leaves such remote objects intact on deletion.
+	// +kubebuilder:validation:MinItems=1
+	Manifests []ObjectSetManifest `json:"manifests"`
+}
+
+// A ObjectSetSpec defines the desired state of a ObjectSet.
+type ObjectSetSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ObjectSetParameters `json:"forProvider"`
+}
+
+// A ObjectSetStatus represents the observed state of a ObjectSet.
+type ObjectSetStatus struct {
+	StatusWithObservedGeneration `json:",inline"`
+	xpv1.ResourceStatus          `json:",inline"`
+	AtProvider                   ObjectSetObservation `json:"atProvider,omitempty"`
+}
+
+// ObjectSetObservation are the observable fields of a ObjectSet.
+type ObjectSetObservation struct {
+	// `manifests` lists the remote objects in the order they are applied.
+	Manifests []ObjectSetManifestObservation `json:"manifests,omitempty"`
+	// `host` is the URL of the API server of the remote cluster.
+	// +optional
+	Host string `json:"host,omitempty"`
+}
+
+// ObjectSetManifestObservation is the observed state of a single remote object of an ObjectSet.
+type ObjectSetManifestObservation struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	Wave       int32  `json:"wave,omitempty"`
+	// `ready` is the readiness of the remote object computed according to the manifest's readiness policy.
+	Ready corev1.ConditionStatus `json:"ready"`
+	// `message` explains why the remote object is not ready.
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ObjectSet is a bundle of kubernetes objects applied in a kind-aware order as one unit.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="PROVIDERCONFIG",type="string",JSONPath=".spec.providerConfigRef.name"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,kubernetes}
+type ObjectSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ObjectSetSpec   `json:"spec"`
+	Status ObjectSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ObjectSetList contains a list of ObjectSet
+type ObjectSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ObjectSet `json:"items"`
+}
+
+// ObjectSet type metadata.
+var (
+	ObjectSetKind             = reflect.TypeOf(ObjectSet{}).Name()
+	ObjectSetGroupKind        = schema.GroupKind{Group: Group, Kind: ObjectSetKind}.String()
+	ObjectSetKindAPIVersion   = ObjectSetKind + "." + SchemeGroupVersion.String()
+	ObjectSetGroupVersionKind = SchemeGroupVersion.WithKind(ObjectSetKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&ObjectSet{}, &ObjectSetList{})
+}
+
+var _ generic.ObservedGenerationSetter = &ObjectSet{}
+
+func (o *ObjectSet) SetObservedGeneration(arg int64) {
+	o.Status.ObservedGeneration = arg
+}
+
+func (m *ObjectSetManifest) GetDesired() (*unstructured.Unstructured, error) {
+	desired := &unstructured.Unstructured{}
+	if err := json.Unmarshal(m.Manifest.Raw, desired); err != nil {
+		return nil, errors.Wrap(err, "cannot unmarshal raw manifest")
+	}
+
+	return desired, nil
+}
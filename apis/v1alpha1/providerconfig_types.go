@@ -29,6 +29,58 @@ import (
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+	// Admission configures the checks of the Objects using this ProviderConfig done by the provider's webhook.
+	// +optional
+	Admission Admission `json:"admission,omitempty"`
+	// Policy restricts the remote objects that the Objects using this ProviderConfig may manage. It's enforced by the
+	// provider's webhook on admission and by the provider before every apply and delete.
+	// +optional
+	Policy Policy `json:"policy,omitempty"`
+}
+
+// Policy restricts the remote objects managed via a ProviderConfig. A remote object is allowed if it matches any of the
+// allow rules, or if there are none, doesn't match any of the deny rules and satisfies the CEL expression, if it's set.
+type Policy struct {
+	// Allow lists the rules matching the remote objects that may be managed. All objects may be managed if it's empty.
+	// +optional
+	Allow []PolicyRule `json:"allow,omitempty"`
+	// Deny lists the rules matching the remote objects that may not be managed, even if they're allowed.
+	// +optional
+	Deny []PolicyRule `json:"deny,omitempty"`
+	// CELExpression must return true for the remote object to be managed. The manifest's `apiVersion`, `kind`,
+	// `metadata`, `spec` and `status` are available as variables, null if the manifest lacks them, e.g.
+	// `!has(spec.hostNetwork)`, and the whole manifest as `self`, e.g. `self.data.size() < 10`.
+	// +optional
+	CELExpression string `json:"celExpression,omitempty"`
+}
+
+// PolicyRule matches remote objects by their kind, namespace and name. The lists hold shell file name patterns, e.g.
+// `team-*`, an empty list matching everything. Cluster-scoped objects have the empty namespace.
+type PolicyRule struct {
+	// Groups of the remote objects' kinds, the empty string being the core group.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+	// Kinds of the remote objects, e.g. `ClusterRoleBinding`.
+	// +optional
+	Kinds []string `json:"kinds,omitempty"`
+	// Namespaces of the remote objects.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Names of the remote objects.
+	// +optional
+	Names []string `json:"names,omitempty"`
+}
+
+// Admission configures the admission-time checks of the Objects using a ProviderConfig.
+type Admission struct {
+	// DryRun enables the server-side dry-run apply of the Objects' manifests against the remote cluster on admission,
+	// so that the manifests rejected by the remote API server are denied right away.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+	// DryRunTimeout is the timeout of the dry-run apply, the Object is admitted with a warning if it's exceeded.
+	// Defaults to 5s.
+	// +optional
+	DryRunTimeout *metav1.Duration `json:"dryRunTimeout,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
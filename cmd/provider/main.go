@@ -34,6 +34,7 @@ import (
 	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
 	"aerf.io/provider-k8s/apis/v1alpha1"
 	"aerf.io/provider-k8s/internal/cacheregistry"
+	"aerf.io/provider-k8s/internal/celcheck"
 	configcontroller "aerf.io/provider-k8s/internal/controllers/config"
 	"aerf.io/provider-k8s/internal/controllers/object"
 )
@@ -43,6 +44,13 @@ type config struct {
 	LeaderElection   bool          `help:"Use leader election for the controller manager."`
 	PollInterval     time.Duration `help:"How often individual resources will be checked for drift from the desired state" default:"1m"`
 	MaxReconcileRate int           `help:"The global maximum rate per second at which resources may checked for drift from the desired state." default:"10"`
+
+	MaxDriftDiffSize int `help:"Maximum size in bytes of the drift diffs kept in statuses and events, 0 means no limit." default:"4096"`
+
+	CELCostLimit         uint64        `help:"Default runtime cost limit of CEL readiness and ProviderConfig policy expressions, 0 means no limit." default:"1000000"`
+	CELEvaluationTimeout time.Duration `help:"Maximum duration of the evaluation of CEL readiness and ProviderConfig policy expressions, 0 means no limit." default:"1s"`
+
+	EnableManagementPolicies bool `help:"Enable support for Management Policies." default:"true"`
 }
 
 func useColoredDevMode(enabled bool) zap.Opts {
@@ -99,8 +107,22 @@ func main() {
 		Features:                &feature.Flags{},
 	}
 
+	if cfg.EnableManagementPolicies {
+		o.Features.Enable(feature.EnableBetaManagementPolicies)
+		log.Info("Beta feature enabled", "flag", feature.EnableBetaManagementPolicies)
+	}
+
 	kctx.FatalIfErrorf(configcontroller.Setup(mgr, o), "Cannot setup %s controller", v1alpha1.ProviderConfigKind)
+	objectCfg := object.Config{
+		MaxDiffSize: cfg.MaxDriftDiffSize,
+		CELLimits: celcheck.Limits{
+			Cost:    cfg.CELCostLimit,
+			Timeout: cfg.CELEvaluationTimeout,
+		},
+	}
 	registry := cacheregistry.New(log.WithValues("name", "cacheRegistry"))
-	kctx.FatalIfErrorf(object.Setup(mgr, o, registry), "Cannot setup %s controller", objv1alpha1.ObjectKind)
+	kctx.FatalIfErrorf(object.Setup(mgr, o, registry, objectCfg), "Cannot setup %s controller", objv1alpha1.ObjectKind)
+	objectSetRegistry := cacheregistry.New(log.WithValues("name", "objectSetCacheRegistry"))
+	kctx.FatalIfErrorf(object.SetupObjectSet(mgr, o, objectSetRegistry, objectCfg), "Cannot setup %s controller", objv1alpha1.ObjectSetKind)
 	kctx.FatalIfErrorf(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
@@ -17,22 +17,36 @@ limitations under the License.
 package main
 
 import (
-	"context"
 	"net/http"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"aerf.io/provider-k8s/apis"
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+	"aerf.io/provider-k8s/internal/celcheck"
+	"aerf.io/provider-k8s/internal/objectindex"
+	providerwebhook "aerf.io/provider-k8s/internal/webhook"
 )
 
 type config struct {
-	Debug bool `help:"Run with debug logging."`
+	Debug                bool          `help:"Run with debug logging."`
+	ProbeProviderConfigs bool          `help:"Call the /version endpoint of the remote clusters of ProviderConfigs on admission, warning when they can't be reached." default:"true"`
+	CELCostLimit         uint64        `help:"Runtime cost limit of CEL ProviderConfig policy expressions, 0 means no limit." default:"1000000"`
+	CELEvaluationTimeout time.Duration `help:"Maximum duration of the evaluation of CEL ProviderConfig policy expressions, 0 means no limit." default:"1s"`
 }
 
 func useColoredDevMode(enabled bool) zap.Opts {
@@ -85,14 +99,52 @@ func main() {
 	})
 	hookServer.Options.WebhookMux = mux
 
-	validatingHook := &webhook.Admission{
-		Handler: admission.HandlerFunc(func(ctx context.Context, req webhook.AdmissionRequest) webhook.AdmissionResponse {
-			log.Info("req", "data", req)
-			return webhook.Allowed("allowed")
-		}),
+	scheme := runtime.NewScheme()
+	kctx.FatalIfErrorf(apis.AddToScheme(scheme), "Cannot add provider APIs to scheme")
+	kctx.FatalIfErrorf(corev1.AddToScheme(scheme), "Cannot add core/v1 APIs to scheme")
+
+	restCfg, err := ctrl.GetConfig()
+	kctx.FatalIfErrorf(err, "Cannot get API server rest config")
+	ctx := signals.SetupSignalHandler()
+	// Objects and ObjectSets are listed by the remote objects they manage from a cache, to check that they're managed only once.
+	objectCache, err := cache.New(restCfg, cache.Options{Scheme: scheme, ByObject: map[client.Object]cache.ByObject{&objv1alpha1.Object{}: {}, &objv1alpha1.ObjectSet{}: {}}})
+	kctx.FatalIfErrorf(err, "Cannot create Object cache")
+	kctx.FatalIfErrorf(objectindex.Setup(ctx, objectCache), "Cannot index Objects and ObjectSets")
+	go func() {
+		kctx.FatalIfErrorf(objectCache.Start(ctx), "Cannot start Object cache")
+	}()
+	if !objectCache.WaitForCacheSync(ctx) {
+		kctx.Fatalf("Cannot sync Object cache")
 	}
-	hookServer.Register("/validate", validatingHook)
+	cli, err := client.New(restCfg, client.Options{
+		Scheme: scheme,
+		Cache:  &client.CacheOptions{Reader: objectCache, DisableFor: []client.Object{&corev1.Secret{}, &apisv1alpha1.ProviderConfig{}}},
+	})
+	kctx.FatalIfErrorf(err, "Cannot create API server client")
+
+	hookServer.Register("/validate-object", admission.WithCustomValidator(scheme, &objv1alpha1.Object{}, &providerwebhook.ObjectValidator{
+		Client: cli,
+		CELLimits: celcheck.Limits{
+			Cost:    cfg.CELCostLimit,
+			Timeout: cfg.CELEvaluationTimeout,
+		},
+	}))
+	hookServer.Register("/validate-objectset", admission.WithCustomValidator(scheme, &objv1alpha1.ObjectSet{}, &providerwebhook.ObjectSetValidator{
+		Client: cli,
+		CELLimits: celcheck.Limits{
+			Cost:    cfg.CELCostLimit,
+			Timeout: cfg.CELEvaluationTimeout,
+		},
+	}))
+	hookServer.Register("/mutate-object", admission.WithCustomDefaulter(scheme, &objv1alpha1.Object{}, &providerwebhook.ObjectDefaulter{
+		Client: cli,
+		Log:    log.WithValues("webhook", "object-defaulter"),
+	}))
+	hookServer.Register("/validate-providerconfig", admission.WithCustomValidator(scheme, &apisv1alpha1.ProviderConfig{}, &providerwebhook.ProviderConfigValidator{
+		Client: cli,
+		Probe:  cfg.ProbeProviderConfigs,
+	}))
 
 	// Start the server without a manger
-	kctx.FatalIfErrorf(hookServer.Start(signals.SetupSignalHandler()))
+	kctx.FatalIfErrorf(hookServer.Start(ctx))
 }
@@ -18,12 +18,17 @@ import (
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"aerf.io/provider-k8s/internal/metrics"
 )
 
 type cacheWithCancel struct {
 	cache    cache.Cache
 	cancelFn context.CancelFunc
 	wg       *sync.WaitGroup
+	// nameNs is the key of the cached object, with the namespace defaulted for namespaced objects.
+	nameNs types.NamespacedName
+	synced bool
 }
 
 type Registry struct {
@@ -65,23 +70,56 @@ func (r *Registry) getCacheWithStopper(key cacheMapKey) (cacheWithCancel, bool)
 func (r *Registry) setCacheWithStopper(key cacheMapKey, val cacheWithCancel) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if _, ok := r.cacheMap[key]; !ok {
+		metrics.ActiveCaches.WithLabelValues(key.HostURL).Inc()
+	}
 	r.cacheMap[key] = val
 }
 
-func (r *Registry) RegisterCacheFromRestConfig(restCfg *rest.Config, gvk schema.GroupVersionKind, nameNs, parentNameNs types.NamespacedName) (retErr error) {
+func (r *Registry) markSynced(key cacheMapKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if val, ok := r.cacheMap[key]; ok {
+		val.synced = true
+		r.cacheMap[key] = val
+	}
+}
+
+func newCacheMapKey(restCfg *rest.Config, gvk schema.GroupVersionKind, nameNs types.NamespacedName) (cacheMapKey, error) {
 	hostURL, versionedAPIPath, err := rest.DefaultServerUrlFor(restCfg)
 	if err != nil {
-		return err
+		return cacheMapKey{}, err
 	}
-	log := r.log.WithValues("name", nameNs.Name, "namespace", nameNs.Namespace, "gvk", gvk, "hostURL", hostURL, "versionedAPIPath", versionedAPIPath)
-	key := cacheMapKey{
+	return cacheMapKey{
 		GVKWithNameNamespace: GVKWithNameNamespace{
 			GroupVersionKind: gvk,
 			NamespacedName:   nameNs,
 		},
 		HostURL:          hostURL.String(),
 		VersionedAPIPath: versionedAPIPath,
+	}, nil
+}
+
+// Get reads obj from the cache registered for it, using obj's GVK, name and namespace as the key.
+// It returns false if there is no synced cache for obj, in which case the caller should read it from the API server instead.
+func (r *Registry) Get(ctx context.Context, restCfg *rest.Config, obj *unstructured.Unstructured) (bool, error) {
+	key, err := newCacheMapKey(restCfg, obj.GroupVersionKind(), client.ObjectKeyFromObject(obj))
+	if err != nil {
+		return false, err
+	}
+	c, ok := r.getCacheWithStopper(key)
+	if !ok || !c.synced {
+		return false, nil
 	}
+	return true, c.cache.Get(ctx, c.nameNs, obj)
+}
+
+func (r *Registry) RegisterCacheFromRestConfig(restCfg *rest.Config, gvk schema.GroupVersionKind, nameNs, parentNameNs types.NamespacedName) (retErr error) {
+	key, err := newCacheMapKey(restCfg, gvk, nameNs)
+	if err != nil {
+		return err
+	}
+	log := r.log.WithValues("name", nameNs.Name, "namespace", nameNs.Namespace, "gvk", gvk, "hostURL", key.HostURL, "versionedAPIPath", key.VersionedAPIPath)
 
 	if _, ok := r.getCacheWithStopper(key); ok {
 		log.Debug("cache already in registry")
@@ -134,10 +172,11 @@ func (r *Registry) RegisterCacheFromRestConfig(restCfg *rest.Config, gvk schema.
 		cache:    c,
 		cancelFn: cancel,
 		wg:       wg,
+		nameNs:   nameNs,
 	})
 	defer func() {
 		if retErr != nil {
-			retErr = multierr.Append(retErr, r.StopAndRemove(restCfg, gvk, nameNs))
+			retErr = multierr.Append(retErr, r.StopAndRemove(restCfg, gvk, key.NamespacedName))
 		}
 	}()
 
@@ -155,6 +194,7 @@ func (r *Registry) RegisterCacheFromRestConfig(restCfg *rest.Config, gvk schema.
 	if !c.WaitForCacheSync(syncCtx) {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
+	r.markSynced(key)
 
 	// ctx background cause informers are already started
 	inf, err := c.GetInformerForKind(context.Background(), gvk)
@@ -180,18 +220,10 @@ func (r *Registry) StopAndRemove(restCfg *rest.Config, gvk schema.GroupVersionKi
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	hostURL, versionedAPIPath, err := rest.DefaultServerUrlFor(restCfg)
+	key, err := newCacheMapKey(restCfg, gvk, childNameNs)
 	if err != nil {
 		return err
 	}
-	key := cacheMapKey{
-		GVKWithNameNamespace: GVKWithNameNamespace{
-			GroupVersionKind: gvk,
-			NamespacedName:   childNameNs,
-		},
-		HostURL:          hostURL.String(),
-		VersionedAPIPath: versionedAPIPath,
-	}
 	c, ok := r.cacheMap[key]
 	if !ok {
 		return nil
@@ -204,5 +236,6 @@ func (r *Registry) StopAndRemove(restCfg *rest.Config, gvk schema.GroupVersionKi
 	c.wg.Wait()
 	log.Debug("waited some time for cache to stop", "duration", time.Since(now))
 	delete(r.cacheMap, key)
+	metrics.ActiveCaches.WithLabelValues(key.HostURL).Dec()
 	return nil
 }
@@ -0,0 +1,110 @@
+package cacheregistry
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// stubCache records the keys read from it, serving the object with that key.
+type stubCache struct {
+	cache.Cache
+	read []client.ObjectKey
+}
+
+func (c *stubCache) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	c.read = append(c.read, key)
+	obj.SetNamespace(key.Namespace)
+	obj.SetName(key.Name)
+	obj.SetResourceVersion("cached")
+	return nil
+}
+
+func TestGet(t *testing.T) {
+	restCfg := &rest.Config{Host: "https://remote.example.com"}
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	cm := func(namespace string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		u.SetNamespace(namespace)
+		u.SetName("cm")
+		return u
+	}
+
+	tests := []struct {
+		name string
+		// registered is the key the cache is registered with, cached the one of the cached object.
+		registered, cached types.NamespacedName
+		synced             bool
+		obj                *unstructured.Unstructured
+		wantCached         bool
+		wantRead           []client.ObjectKey
+	}{
+		{
+			name: "no cache",
+			obj:  cm("default"),
+		},
+		{
+			name:       "cache not synced yet",
+			registered: types.NamespacedName{Namespace: "default", Name: "cm"},
+			cached:     types.NamespacedName{Namespace: "default", Name: "cm"},
+			obj:        cm("default"),
+		},
+		{
+			name:       "synced cache",
+			registered: types.NamespacedName{Namespace: "default", Name: "cm"},
+			cached:     types.NamespacedName{Namespace: "default", Name: "cm"},
+			synced:     true,
+			obj:        cm("default"),
+			wantCached: true,
+			wantRead:   []client.ObjectKey{{Namespace: "default", Name: "cm"}},
+		},
+		{
+			name:       "defaulted namespace",
+			registered: types.NamespacedName{Name: "cm"},
+			cached:     types.NamespacedName{Namespace: "default", Name: "cm"},
+			synced:     true,
+			obj:        cm(""),
+			wantCached: true,
+			wantRead:   []client.ObjectKey{{Namespace: "default", Name: "cm"}},
+		},
+		{
+			name:       "cache of another object",
+			registered: types.NamespacedName{Namespace: "other", Name: "cm"},
+			cached:     types.NamespacedName{Namespace: "other", Name: "cm"},
+			synced:     true,
+			obj:        cm("default"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(logging.NewNopLogger())
+			c := &stubCache{}
+			if tt.registered.Name != "" {
+				key, err := newCacheMapKey(restCfg, gvk, tt.registered)
+				require.NoError(t, err)
+				r.setCacheWithStopper(key, cacheWithCancel{cache: c, cancelFn: func() {}, wg: &sync.WaitGroup{}, nameNs: tt.cached})
+				if tt.synced {
+					r.markSynced(key)
+				}
+			}
+
+			cached, err := r.Get(context.Background(), restCfg, tt.obj)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCached, cached)
+			require.Equal(t, tt.wantRead, c.read)
+			if tt.wantCached {
+				require.Equal(t, "cached", tt.obj.GetResourceVersion())
+			}
+		})
+	}
+}
@@ -1,23 +1,39 @@
 package celcheck
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types/ref"
 	"github.com/google/cel-go/ext"
+	"github.com/google/cel-go/interpreter"
+	"google.golang.org/protobuf/types/known/structpb"
+	apiservercel "k8s.io/apiserver/pkg/cel"
+	"k8s.io/apiserver/pkg/cel/common"
 	"k8s.io/apiserver/pkg/cel/library"
+	"k8s.io/apiserver/pkg/cel/openapi"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/utils/lru"
+
+	"aerf.io/provider-k8s/internal/metrics"
 )
 
+// Aggregate literals aren't required to be homogeneous, so that readiness expressions can return maps with
+// values of different types, see EvalReadiness.
 var celEnvOptions = []cel.EnvOption{
 	cel.EagerlyValidateDeclarations(true),
 	cel.DefaultUTCTimeZone(true),
-	cel.HomogeneousAggregateLiterals(),
 	cel.ASTValidators(
 		cel.ValidateDurationLiterals(),
 		cel.ValidateTimestampLiterals(),
 		cel.ValidateRegexLiterals(),
-		cel.ValidateHomogeneousAggregateLiterals(),
 	),
 	ext.Bindings(),
 	ext.Encoders(),
@@ -33,31 +49,56 @@ var celEnvOptions = []cel.EnvOption{
 
 var celProgramOptions = []cel.ProgramOption{
 	cel.EvalOptions(cel.OptOptimize, cel.OptTrackCost),
+	cel.InterruptCheckFrequency(interruptCheckFrequency),
+}
+
+// interruptCheckFrequency is the number of comprehension iterations between the checks whether the evaluation timed out.
+const interruptCheckFrequency = 100
+
+// Limits bound the resources a single evaluation may use. Zero values mean no limit.
+type Limits struct {
+	// Cost is the maximum runtime cost of the evaluation, which roughly corresponds to the number of operations it performs.
+	Cost uint64
+	// Timeout is the maximum duration of the evaluation.
+	Timeout time.Duration
 }
 
+// SelfVariable is the name of the variable holding the whole input. It's typed with the input's OpenAPI schema if one
+// is given, otherwise it's dynamically typed, like the variables holding the input's top-level keys.
+const SelfVariable = "self"
+
+// ErrLimitExceeded is returned when an evaluation is stopped for exceeding its Limits.
+var ErrLimitExceeded = errors.New("CEL expression exceeded its evaluation limits")
+
+// ErrNoSuchKey is returned when an evaluation reads a key missing from the input, e.g. a status field that isn't
+// reported yet.
+var ErrNoSuchKey = errors.New("no such key")
+
 // slightly adapted https://github.com/undistro/cel-playground/blob/a015ab6d50145af7397bc9e382b23429b57d4c6c/eval/eval.go#L45
 // Eval evaluates the cel expression against the given input. Expression must return bool value.
-func Eval(exp string, input map[string]any) (bool, error) {
-	inputVars := make([]cel.EnvOption, 0, len(input))
-	for k := range input {
-		inputVars = append(inputVars, cel.Variable(k, cel.DynType))
-	}
-	env, err := cel.NewEnv(append(celEnvOptions, inputVars...)...)
+// Evaluations exceeding the limits fail with an error wrapping ErrLimitExceeded.
+func Eval(exp string, input map[string]any, limits Limits) (bool, error) {
+	val, err := eval(exp, input, nil, nil, limits)
 	if err != nil {
-		return false, fmt.Errorf("failed to create CEL env: %s", err)
+		return false, err
 	}
-	ast, issues := env.Compile(exp)
-	if issues != nil {
-		return false, fmt.Errorf("failed to compile the CEL expression: %s", issues.String())
-	}
-	prog, err := env.Program(ast, celProgramOptions...)
-	if err != nil {
-		return false, fmt.Errorf("failed to instantiate CEL program: %s", err)
+	return toBool(val)
+}
+
+// EvalWithVariables is like Eval, but declares the given variables instead of the input's top-level keys, bound to
+// the input's values or null if the input lacks them, so that the expression compiles the same way for any input.
+func EvalWithVariables(exp string, input map[string]any, variables []string, limits Limits) (bool, error) {
+	if variables == nil {
+		variables = []string{}
 	}
-	val, _, err := prog.Eval(input)
+	val, err := eval(exp, input, variables, nil, limits)
 	if err != nil {
-		return false, fmt.Errorf("failed to evaluate: %s", err)
+		return false, err
 	}
+	return toBool(val)
+}
+
+func toBool(val ref.Val) (bool, error) {
 	anyBool, err := val.ConvertToNative(reflect.TypeOf(true))
 	if err != nil {
 		return false, fmt.Errorf("failed to marshal the output to bool: %s", err)
@@ -65,3 +106,253 @@ func Eval(exp string, input map[string]any) (bool, error) {
 
 	return anyBool.(bool), nil //nolint:forcetypeassert // it's checked by ConvertToNative
 }
+
+// Readiness is the result of a readiness expression.
+type Readiness struct {
+	Ready   bool
+	Reason  string
+	Message string
+}
+
+// EvalReadiness evaluates the readiness cel expression against the given input. Expression must return one of:
+//   - a bool, telling whether the input is ready,
+//   - a map with the bool `ready` key and optional string `reason` and `message` keys,
+//   - a list of strings describing the failed checks, where the empty list means the input is ready.
+//
+// If the input's schema is given, the expression is type-checked against it on the first use, with absent fields
+// behaving like in the validation rules of CustomResourceDefinitions.
+// Evaluations exceeding the limits fail with an error wrapping ErrLimitExceeded.
+func EvalReadiness(exp string, input map[string]any, schema *spec.Schema, limits Limits) (Readiness, error) {
+	val, err := eval(exp, input, nil, schema, limits)
+	if err != nil {
+		return Readiness{}, err
+	}
+	out, err := toJSONValue(val)
+	if err != nil {
+		return Readiness{}, err
+	}
+
+	switch out := out.(type) {
+	case bool:
+		return Readiness{Ready: out}, nil
+	case map[string]any:
+		return readinessFromMap(out)
+	case []any:
+		return readinessFromFailedChecks(out)
+	default:
+		return Readiness{}, fmt.Errorf("expected bool, map or list output, got %T", out)
+	}
+}
+
+func readinessFromMap(out map[string]any) (Readiness, error) {
+	res := Readiness{}
+	var ok bool
+	if res.Ready, ok = out["ready"].(bool); !ok {
+		return Readiness{}, fmt.Errorf("expected bool under the ready key, got %T", out["ready"])
+	}
+	for key, dst := range map[string]*string{"reason": &res.Reason, "message": &res.Message} {
+		v, found := out[key]
+		if !found {
+			continue
+		}
+		if *dst, ok = v.(string); !ok {
+			return Readiness{}, fmt.Errorf("expected string under the %s key, got %T", key, v)
+		}
+	}
+	for key := range out {
+		if key != "ready" && key != "reason" && key != "message" {
+			return Readiness{}, fmt.Errorf("unexpected key %q, only ready, reason and message are allowed", key)
+		}
+	}
+	return res, nil
+}
+
+func readinessFromFailedChecks(out []any) (Readiness, error) {
+	failed := make([]string, 0, len(out))
+	for i, v := range out {
+		check, ok := v.(string)
+		if !ok {
+			return Readiness{}, fmt.Errorf("expected list of strings, got %T at index %d", v, i)
+		}
+		failed = append(failed, check)
+	}
+	return Readiness{Ready: len(failed) == 0, Message: strings.Join(failed, "; ")}, nil
+}
+
+// Compile compiles the cel expression in the environment used for evaluation, declaring the given variables and an
+// untyped self, so that syntax errors and references to undeclared variables are reported before the first evaluation.
+func Compile(exp string, variables []string) error {
+	_, err := compile(exp, declaredVariables(variables), nil, 0)
+	return err
+}
+
+// declaredVariables returns the sorted, unique names of the variables declared along with self.
+func declaredVariables(names []string) []string {
+	vars := make([]string, 0, len(names))
+	for _, v := range names {
+		if v != SelfVariable {
+			vars = append(vars, v)
+		}
+	}
+	sort.Strings(vars)
+	return slices.Compact(vars)
+}
+
+// EvalValue evaluates the cel expression against the given input and returns its result as a JSON-compatible value,
+// e.g. string, float64, bool, []any or map[string]any.
+func EvalValue(exp string, input map[string]any, limits Limits) (any, error) {
+	val, err := eval(exp, input, nil, nil, limits)
+	if err != nil {
+		return nil, err
+	}
+	return toJSONValue(val)
+}
+
+func toJSONValue(val ref.Val) (any, error) {
+	jsonVal, err := val.ConvertToNative(reflect.TypeOf(&structpb.Value{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the output to JSON value: %s", err)
+	}
+
+	return jsonVal.(*structpb.Value).AsInterface(), nil //nolint:forcetypeassert // it's checked by ConvertToNative
+}
+
+// eval evaluates the expression declaring the given variables, or the input's top-level keys if they're nil, and self.
+func eval(exp string, input map[string]any, variables []string, schema *spec.Schema, limits Limits) (ref.Val, error) {
+	if variables == nil {
+		variables = make([]string, 0, len(input))
+		for k := range input {
+			variables = append(variables, k)
+		}
+	}
+	variables = declaredVariables(variables)
+	prog, selfSchema, err := program(exp, variables, schema, limits.Cost)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]any, len(variables)+1)
+	for _, k := range variables {
+		vars[k] = input[k]
+	}
+	vars[SelfVariable] = input
+	if selfSchema != nil {
+		vars[SelfVariable] = common.UnstructuredToVal(input, &openapi.Schema{Schema: selfSchema})
+	}
+
+	ctx := context.Background()
+	if limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.Timeout)
+		defer cancel()
+	}
+	val, _, err := prog.ContextEval(ctx, vars)
+	var cancelled interpreter.EvalCancelledError
+	switch {
+	case errors.As(err, &cancelled) && cancelled.Cause == interpreter.CostLimitExceeded:
+		return nil, fmt.Errorf("%w: runtime cost exceeded the limit of %d", ErrLimitExceeded, limits.Cost)
+	case err != nil && ctx.Err() != nil:
+		return nil, fmt.Errorf("%w: evaluation took longer than %s", ErrLimitExceeded, limits.Timeout)
+	case err != nil && strings.HasPrefix(err.Error(), ErrNoSuchKey.Error()):
+		return nil, fmt.Errorf("failed to evaluate: %w%s", ErrNoSuchKey, strings.TrimPrefix(err.Error(), ErrNoSuchKey.Error()))
+	case err != nil:
+		return nil, fmt.Errorf("failed to evaluate: %s", err)
+	}
+	return val, nil
+}
+
+// selfTypeName is the name of the type of self declared from the input's schema.
+const selfTypeName = "selfType"
+
+// programCacheSize is the maximum number of compiled programs kept in programs.
+const programCacheSize = 1024
+
+// programs caches the compiled programs, so that evaluating the same expression against similarly shaped inputs,
+// e.g. on every poll of an Object, costs only the evaluation itself. The cache is safe for concurrent use.
+var programs = lru.New(programCacheSize)
+
+// programKey identifies a compiled program. Variables are the sorted, comma separated names of the variables declared
+// in the program's environment. Schemas are the ones passed by the callers, compared by pointer,
+// so callers should reuse them.
+type programKey struct {
+	expression string
+	variables  string
+	schema     *spec.Schema
+	costLimit  uint64
+}
+
+// cachedProgram is a compiled program along with the schema of self it was compiled with, i.e. the caller's schema
+// completed with apiVersion, kind and metadata.
+type cachedProgram struct {
+	program    cel.Program
+	selfSchema *spec.Schema
+}
+
+// program returns the compiled program of the expression declaring the sorted variables and self, compiling it on the
+// first use, and the schema of self. Compilation errors aren't cached.
+func program(exp string, vars []string, schema *spec.Schema, costLimit uint64) (cel.Program, *spec.Schema, error) {
+	key := programKey{expression: exp, variables: strings.Join(vars, ","), schema: schema, costLimit: costLimit}
+	if cached, ok := programs.Get(key); ok {
+		metrics.CELProgramCacheHits.Inc()
+		cp := cached.(cachedProgram) //nolint:forcetypeassert // only programs are added to the cache
+		return cp.program, cp.selfSchema, nil
+	}
+	metrics.CELProgramCacheMisses.Inc()
+
+	var selfSchema *spec.Schema
+	if schema != nil {
+		selfSchema = common.WithTypeAndObjectMeta(schema)
+	}
+	prog, err := compile(exp, vars, selfSchema, costLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	programs.Add(key, cachedProgram{program: prog, selfSchema: selfSchema})
+	return prog, selfSchema, nil
+}
+
+func compile(exp string, vars []string, schema *spec.Schema, costLimit uint64) (cel.Program, error) {
+	defer func(start time.Time) {
+		metrics.CELCompileDuration.Observe(time.Since(start).Seconds())
+	}(time.Now())
+
+	env, err := cel.NewEnv(celEnvOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL env: %s", err)
+	}
+	envOpts := make([]cel.EnvOption, 0, len(vars)+3)
+	for _, k := range vars {
+		envOpts = append(envOpts, cel.Variable(k, cel.DynType))
+	}
+	selfType := cel.DynType
+	if schema != nil {
+		if declType := common.SchemaDeclType(&openapi.Schema{Schema: schema}, true); declType != nil {
+			declType = declType.MaybeAssignTypeName(selfTypeName)
+			//nolint:staticcheck // DeclTypeProvider still requires the deprecated ref.TypeProvider in this version
+			providerOpts, err := apiservercel.NewDeclTypeProvider(declType).EnvOptions(env.TypeProvider())
+			if err != nil {
+				return nil, fmt.Errorf("failed to declare the schema's types: %s", err)
+			}
+			envOpts = append(envOpts, providerOpts...)
+			selfType = declType.CelType()
+		}
+	}
+	envOpts = append(envOpts, cel.Variable(SelfVariable, selfType))
+	if env, err = env.Extend(envOpts...); err != nil {
+		return nil, fmt.Errorf("failed to create CEL env: %s", err)
+	}
+
+	ast, issues := env.Compile(exp)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("failed to compile the CEL expression: %s", issues.String())
+	}
+	progOpts := celProgramOptions
+	if costLimit > 0 {
+		progOpts = append(progOpts[:len(progOpts):len(progOpts)], cel.CostLimit(costLimit))
+	}
+	prog, err := env.Program(ast, progOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate CEL program: %s", err)
+	}
+	return prog, nil
+}
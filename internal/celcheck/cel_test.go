@@ -2,11 +2,15 @@ package celcheck_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
 
 	"aerf.io/provider-k8s/internal/celcheck"
+	"aerf.io/provider-k8s/internal/metrics"
 )
 
 // tests based on example input from https://playcel.undistro.io/ + my own
@@ -107,7 +111,7 @@ status.availableReplicas == spec.replicas`,
 			input := make(map[string]any)
 			require.NoError(t, yaml.Unmarshal([]byte(tt.input), &input))
 
-			got, err := celcheck.Eval(tt.expression, input)
+			got, err := celcheck.Eval(tt.expression, input, celcheck.Limits{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Eval() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -119,6 +123,291 @@ status.availableReplicas == spec.replicas`,
 	}
 }
 
+func TestEvalValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		input      string
+		want       any
+		wantErr    error
+	}{
+		{
+			name:       "string",
+			expression: `status.loadBalancer.ingress[0].ip`,
+			input: `status:
+  loadBalancer:
+    ingress:
+    - ip: 10.0.0.1
+`,
+			want: "10.0.0.1",
+		},
+		{
+			name:       "number",
+			expression: `spec.replicas * 2`,
+			input:      readyDeploy,
+			want:       float64(2),
+		},
+		{
+			name:       "map",
+			expression: `{"name": metadata.name, "namespace": metadata.namespace}`,
+			input:      readyDeploy,
+			want:       map[string]any{"name": "deploy-name", "namespace": "default"},
+		},
+		{
+			name:       "list",
+			expression: `status.conditions.map(c, c.type)`,
+			input:      readyDeploy,
+			want:       []any{"Progressing", "Available"},
+		},
+		{
+			name:       "missing key",
+			expression: `status.loadBalancer`,
+			input:      readyDeploy,
+			wantErr:    celcheck.ErrNoSuchKey,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := make(map[string]any)
+			require.NoError(t, yaml.Unmarshal([]byte(tt.input), &input))
+
+			got, err := celcheck.EvalValue(tt.expression, input, celcheck.Limits{})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvalReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       celcheck.Readiness
+		wantErr    bool
+	}{
+		{
+			name:       "bool",
+			expression: `status.availableReplicas == spec.replicas`,
+			want:       celcheck.Readiness{Ready: true},
+		},
+		{
+			name:       "map",
+			expression: `{"ready": status.availableReplicas > 1, "reason": "NotEnoughReplicas", "message": "available replicas: " + string(status.availableReplicas)}`,
+			want:       celcheck.Readiness{Reason: "NotEnoughReplicas", Message: "available replicas: 1"},
+		},
+		{
+			name:       "map without reason and message",
+			expression: `{"ready": true}`,
+			want:       celcheck.Readiness{Ready: true},
+		},
+		{
+			name:       "failed checks",
+			expression: `[status.availableReplicas > 1 ? "" : "not enough replicas", has(status.loadBalancer) ? "" : "no load balancer"].filter(c, c != "")`,
+			want:       celcheck.Readiness{Message: "not enough replicas; no load balancer"},
+		},
+		{
+			name:       "no failed checks",
+			expression: `[status.availableReplicas > 0 ? "" : "no replicas"].filter(c, c != "")`,
+			want:       celcheck.Readiness{Ready: true},
+		},
+		{
+			name:       "map without ready",
+			expression: `{"reason": "Unknown"}`,
+			wantErr:    true,
+		},
+		{
+			name:       "map with unknown key",
+			expression: `{"ready": true, "msg": "typo"}`,
+			wantErr:    true,
+		},
+		{
+			name:       "list of numbers",
+			expression: `[1, 2]`,
+			wantErr:    true,
+		},
+		{
+			name:       "string",
+			expression: `metadata.name`,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := make(map[string]any)
+			require.NoError(t, yaml.Unmarshal([]byte(readyDeploy), &input))
+
+			got, err := celcheck.EvalReadiness(tt.expression, input, nil, celcheck.Limits{})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvalReadinessTypedSelf(t *testing.T) {
+	schema := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"spec": {SchemaProps: spec.SchemaProps{
+				Type:       []string{"object"},
+				Properties: map[string]spec.Schema{"replicas": *spec.Int64Property()},
+			}},
+			"status": {SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"availableReplicas": *spec.Int64Property(),
+					"readyReplicas":     *spec.Int64Property(),
+				},
+			}},
+		},
+	}}
+	input := map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "deploy"},
+		"spec":       map[string]any{"replicas": int64(2)},
+		"status":     map[string]any{"availableReplicas": int64(2)},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		schema     *spec.Schema
+		want       celcheck.Readiness
+		wantErr    bool
+	}{
+		{
+			name:       "typed self",
+			expression: `self.status.availableReplicas == self.spec.replicas && self.metadata.name == "deploy"`,
+			schema:     schema,
+			want:       celcheck.Readiness{Ready: true},
+		},
+		{
+			name:       "absent optional field",
+			expression: `!has(self.status.readyReplicas) && self.status.?readyReplicas.orValue(0) == 0`,
+			schema:     schema,
+			want:       celcheck.Readiness{Ready: true},
+		},
+		{
+			name:       "typo is caught when compiling",
+			expression: `self.status.availableReplica == 2`,
+			schema:     schema,
+			wantErr:    true,
+		},
+		{
+			name:       "type mismatch is caught when compiling",
+			expression: `self.status.availableReplicas == "2"`,
+			schema:     schema,
+			wantErr:    true,
+		},
+		{
+			name:       "untyped self without schema",
+			expression: `self.status.availableReplicas == status.availableReplicas`,
+			want:       celcheck.Readiness{Ready: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := celcheck.EvalReadiness(tt.expression, input, tt.schema, celcheck.Limits{})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvalLimits(t *testing.T) {
+	input := map[string]any{"items": func() []any {
+		items := make([]any, 1000)
+		for i := range items {
+			items[i] = i
+		}
+		return items
+	}()}
+	quadratic := `items.all(i, items.all(j, i + j >= 0))`
+
+	_, err := celcheck.Eval(quadratic, input, celcheck.Limits{Cost: 1000})
+	require.ErrorIs(t, err, celcheck.ErrLimitExceeded)
+
+	_, err = celcheck.Eval(quadratic, input, celcheck.Limits{Timeout: time.Millisecond})
+	require.ErrorIs(t, err, celcheck.ErrLimitExceeded)
+
+	got, err := celcheck.Eval(`items.size() == 1000`, input, celcheck.Limits{Cost: 1000, Timeout: time.Second})
+	require.NoError(t, err)
+	require.True(t, got)
+}
+
+func TestEvalWithVariables(t *testing.T) {
+	variables := []string{"metadata", "spec"}
+
+	got, err := celcheck.EvalWithVariables(`!has(spec.hostNetwork) && !has(self.spec)`, map[string]any{"metadata": map[string]any{"name": "cm"}}, variables, celcheck.Limits{})
+	require.NoError(t, err)
+	require.True(t, got, "variables missing from the input should be null")
+
+	got, err = celcheck.EvalWithVariables(`has(spec.hostNetwork) && self.data.size() == 0`, map[string]any{"spec": map[string]any{"hostNetwork": true}, "data": map[string]any{}}, variables, celcheck.Limits{})
+	require.NoError(t, err)
+	require.True(t, got)
+
+	err = celcheck.Compile(`data.size() == 0`, variables)
+	require.Error(t, err, "only the given variables should be declared")
+	_, err = celcheck.EvalWithVariables(`data.size() == 0`, map[string]any{"data": map[string]any{}}, variables, celcheck.Limits{})
+	require.Error(t, err, "only the given variables should be declared")
+}
+
+func TestEvalCachesPrograms(t *testing.T) {
+	const exp = `has(spec.cacheTest) && spec.cacheTest`
+	hits := testutil.ToFloat64(metrics.CELProgramCacheHits)
+	misses := testutil.ToFloat64(metrics.CELProgramCacheMisses)
+
+	for _, input := range []map[string]any{
+		{"spec": map[string]any{"cacheTest": true}},
+		{"spec": map[string]any{"cacheTest": false}, "status": map[string]any{}},
+		{"status": map[string]any{}, "spec": map[string]any{"cacheTest": true}},
+	} {
+		_, err := celcheck.Eval(exp, input, celcheck.Limits{})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, float64(1), testutil.ToFloat64(metrics.CELProgramCacheHits)-hits, "inputs with the same keys should reuse the program")
+	require.Equal(t, float64(2), testutil.ToFloat64(metrics.CELProgramCacheMisses)-misses, "inputs with different keys should compile a new program")
+}
+
+func TestEvalCachesProgramsWithSchema(t *testing.T) {
+	const exp = `self.spec.cacheTest`
+	schema := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"spec": {SchemaProps: spec.SchemaProps{
+				Type:       []string{"object"},
+				Properties: map[string]spec.Schema{"cacheTest": *spec.BooleanProperty()},
+			}},
+		},
+	}}
+	other := *schema
+	input := map[string]any{"spec": map[string]any{"cacheTest": true}}
+	hits := testutil.ToFloat64(metrics.CELProgramCacheHits)
+	misses := testutil.ToFloat64(metrics.CELProgramCacheMisses)
+
+	for _, s := range []*spec.Schema{schema, schema, schema, &other} {
+		got, err := celcheck.EvalReadiness(exp, input, s, celcheck.Limits{})
+		require.NoError(t, err)
+		require.Equal(t, celcheck.Readiness{Ready: true}, got)
+	}
+
+	require.Equal(t, float64(2), testutil.ToFloat64(metrics.CELProgramCacheHits)-hits, "the same schema should reuse the program")
+	require.Equal(t, float64(2), testutil.ToFloat64(metrics.CELProgramCacheMisses)-misses, "another schema should compile a new program")
+}
+
 const readyDeploy = `apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -0,0 +1,89 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+)
+
+const defaultFieldManager = "provider-k8s"
+
+func fieldManager(opts objv1alpha1.Apply) string {
+	if opts.FieldManager == "" {
+		return defaultFieldManager
+	}
+	return opts.FieldManager
+}
+
+func shouldForce(opts objv1alpha1.Apply) bool {
+	return opts.Force == nil || *opts.Force
+}
+
+// fieldConflict is a single server-side apply conflict reported by the API server.
+type fieldConflict struct {
+	// field is the conflicting field path, e.g. `.spec.replicas`.
+	field string
+	// manager is the field manager owning the field, e.g. `"kubectl" using apps/v1`.
+	manager string
+}
+
+// fieldConflictsOf returns the server-side apply conflicts carried by err, if any.
+func fieldConflictsOf(err error) []fieldConflict {
+	if !apierrors.IsConflict(err) {
+		return nil
+	}
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return nil
+	}
+	var conflicts []fieldConflict
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflicts = append(conflicts, fieldConflict{
+			field:   cause.Field,
+			manager: strings.TrimPrefix(cause.Message, "conflict with "),
+		})
+	}
+	return conflicts
+}
+
+// areForceable reports whether every conflicting field is listed in forceFields or nested under one of them.
+func areForceable(conflicts []fieldConflict, forceFields []string) bool {
+	for _, c := range conflicts {
+		if !isForceable(c.field, forceFields) {
+			return false
+		}
+	}
+	return true
+}
+
+func isForceable(field string, forceFields []string) bool {
+	field = strings.TrimPrefix(field, ".")
+	for _, f := range forceFields {
+		f = strings.TrimPrefix(f, ".")
+		if field == f || strings.HasPrefix(field, f+".") || strings.HasPrefix(field, f+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// describeConflicts tells which of the conflicting fields are owned by which field managers.
+func describeConflicts(conflicts []fieldConflict) string {
+	owned := make([]string, 0, len(conflicts))
+	for _, c := range conflicts {
+		owned = append(owned, fmt.Sprintf("%s is managed by %s", c.field, c.manager))
+	}
+	return strings.Join(owned, "; ")
+}
+
+func conflictError(conflicts []fieldConflict) error {
+	return errors.Errorf("apply conflicts with other field managers, enable spec.apply.force or list the fields in spec.apply.forceFields to take them over: %s", describeConflicts(conflicts))
+}
@@ -0,0 +1,68 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFieldConflicts(t *testing.T) {
+	conflictErr := apierrors.NewApplyConflict([]metav1.StatusCause{
+		{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kube-controller-manager" using apps/v1`, Field: ".spec.replicas"},
+		{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kubectl-edit" using apps/v1`, Field: `.spec.template.spec.containers[name="nginx"].image`},
+	}, "Apply failed with 2 conflicts")
+
+	tests := []struct {
+		name          string
+		err           error
+		forceFields   []string
+		wantConflicts []fieldConflict
+		wantForceable bool
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name: "other error",
+			err:  errors.New("boom"),
+		},
+		{
+			name:        "conflicts not listed in forceFields",
+			err:         conflictErr,
+			forceFields: []string{"spec.replicas"},
+			wantConflicts: []fieldConflict{
+				{field: ".spec.replicas", manager: `"kube-controller-manager" using apps/v1`},
+				{field: `.spec.template.spec.containers[name="nginx"].image`, manager: `"kubectl-edit" using apps/v1`},
+			},
+		},
+		{
+			name:        "conflicts nested under forceFields",
+			err:         errors.Wrap(conflictErr, "wrapped"),
+			forceFields: []string{"spec.replicas", ".spec.template.spec.containers"},
+			wantConflicts: []fieldConflict{
+				{field: ".spec.replicas", manager: `"kube-controller-manager" using apps/v1`},
+				{field: `.spec.template.spec.containers[name="nginx"].image`, manager: `"kubectl-edit" using apps/v1`},
+			},
+			wantForceable: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts := fieldConflictsOf(tt.err)
+			require.Equal(t, tt.wantConflicts, conflicts)
+			if len(conflicts) > 0 {
+				require.Equal(t, tt.wantForceable, areForceable(conflicts, tt.forceFields))
+			}
+		})
+	}
+}
+
+func TestIsForceable(t *testing.T) {
+	require.True(t, isForceable(".spec.replicas", []string{"spec.replicas"}))
+	require.True(t, isForceable(".spec.template.metadata", []string{"spec.template"}))
+	require.False(t, isForceable(".spec.replicasCount", []string{"spec.replicas"}))
+	require.False(t, isForceable(".spec.replicas", nil))
+}
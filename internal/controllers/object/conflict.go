@@ -0,0 +1,49 @@
+package object
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	"aerf.io/provider-k8s/internal/objectindex"
+)
+
+const (
+	// typeRemoteObjectConflict is the condition telling whether the remote object is managed by another Object or ObjectSet.
+	typeRemoteObjectConflict xpv1.ConditionType = "RemoteObjectConflict"
+
+	reasonManagedByAnotherObject xpv1.ConditionReason = "ManagedByAnotherObject"
+	reasonNoConflict             xpv1.ConditionReason = "NoConflict"
+)
+
+// remoteObjectOwner returns the other Object or ObjectSet managing the same remote object, which is then left to it,
+// or an empty string if the Object owns the remote object. It records the outcome in the Object's conditions.
+func (e *external) remoteObjectOwner(ctx context.Context, cr *objv1alpha1.Object, desired *unstructured.Unstructured) (string, error) {
+	owner, err := objectindex.Owner(ctx, e.localCli, cr, e.remoteRestCfg.Host, desired)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case owner != "":
+		cr.SetConditions(xpv1.Condition{
+			Type:               typeRemoteObjectConflict,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.Now(),
+			Reason:             reasonManagedByAnotherObject,
+			Message:            "Remote object is already managed by " + owner,
+		})
+	case cr.GetCondition(typeRemoteObjectConflict).Status == corev1.ConditionTrue:
+		cr.SetConditions(xpv1.Condition{
+			Type:               typeRemoteObjectConflict,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+			Reason:             reasonNoConflict,
+		})
+	}
+	return owner, nil
+}
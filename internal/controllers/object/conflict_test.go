@@ -0,0 +1,67 @@
+package object
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	"aerf.io/provider-k8s/internal/objectindex"
+)
+
+func TestRemoteObjectOwner(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, objv1alpha1.SchemeBuilder.AddToScheme(scheme))
+
+	const host = "https://remote.example.com"
+	now := time.Now()
+	object := func(name string, created time.Time) *objv1alpha1.Object {
+		o := &objv1alpha1.Object{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Spec: objv1alpha1.ObjectSpec{
+				ForProvider: objv1alpha1.ObjectParameters{Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"default"}}`)}},
+			},
+		}
+		o.Status.AtProvider.Host = host
+		return o
+	}
+	owner := object("owner", now.Add(-time.Hour))
+	e := &external{
+		localCli: fake.NewClientBuilder().WithScheme(scheme).WithObjects(owner).
+			WithIndex(&objv1alpha1.Object{}, objectindex.Field, objectindex.IndexObject).
+			WithIndex(&objv1alpha1.ObjectSet{}, objectindex.Field, objectindex.IndexObjectSet).
+			Build(),
+		remoteRestCfg: &rest.Config{Host: host},
+	}
+
+	cr := object("duplicate", now)
+	desired, err := cr.GetDesired()
+	require.NoError(t, err)
+	got, err := e.remoteObjectOwner(context.Background(), cr, desired)
+	require.NoError(t, err)
+	require.Equal(t, `Object "owner"`, got)
+	cond := cr.GetCondition(typeRemoteObjectConflict)
+	require.Equal(t, corev1.ConditionTrue, cond.Status)
+	require.Contains(t, cond.Message, `"owner"`)
+
+	// the conflict is resolved once the owner is gone
+	require.NoError(t, e.localCli.Delete(context.Background(), owner))
+	got, err = e.remoteObjectOwner(context.Background(), cr, desired)
+	require.NoError(t, err)
+	require.Empty(t, got)
+	require.Equal(t, corev1.ConditionFalse, cr.GetCondition(typeRemoteObjectConflict).Status)
+
+	// no condition is set on Objects that were never in conflict
+	cr = object("other", now)
+	_, err = e.remoteObjectOwner(context.Background(), cr, desired)
+	require.NoError(t, err)
+	require.Equal(t, xpv1.Condition{Type: typeRemoteObjectConflict, Status: corev1.ConditionUnknown}, cr.GetCondition(typeRemoteObjectConflict))
+}
@@ -0,0 +1,74 @@
+package object
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	"aerf.io/provider-k8s/internal/celcheck"
+)
+
+// connectionDetails reads the values of the connection details from the observed remote object.
+// Fields that are missing, e.g. an IP of a LoadBalancer that is not assigned yet, are skipped.
+func connectionDetails(details []objv1alpha1.ConnectionDetail, celLimits celcheck.Limits, observed *unstructured.Unstructured) (managed.ConnectionDetails, error) {
+	if len(details) == 0 {
+		return nil, nil
+	}
+
+	conn := make(managed.ConnectionDetails, len(details))
+	for _, d := range details {
+		var val any
+		var err error
+		switch {
+		case d.FieldPath != "":
+			val, err = fieldpath.Pave(observed.Object).GetValue(d.FieldPath)
+			if fieldpath.IsNotFound(err) {
+				continue
+			}
+			if err == nil && isSecretData(observed, d.FieldPath) {
+				val, err = decodeSecretData(val)
+			}
+		case d.CELExpression != "":
+			val, err = celcheck.EvalValue(d.CELExpression, observed.UnstructuredContent(), celLimits)
+			if errors.Is(err, celcheck.ErrNoSuchKey) {
+				continue
+			}
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get the value of connection detail %q", d.Name)
+		}
+
+		if conn[d.Name], err = connectionDetailValue(val); err != nil {
+			return nil, errors.Wrapf(err, "cannot marshal the value of connection detail %q", d.Name)
+		}
+	}
+	return conn, nil
+}
+
+func isSecretData(obj *unstructured.Unstructured, path string) bool {
+	return obj.GroupVersionKind() == corev1.SchemeGroupVersion.WithKind("Secret") && (strings.HasPrefix(path, "data.") || strings.HasPrefix(path, "data["))
+}
+
+func decodeSecretData(val any) (any, error) {
+	s, ok := val.(string)
+	if !ok {
+		return nil, errors.Errorf("secret data should be a string, got %T", val)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	return string(decoded), errors.Wrap(err, "cannot decode secret data")
+}
+
+// connectionDetailValue returns strings as they are and any other value marshalled to JSON.
+func connectionDetailValue(val any) ([]byte, error) {
+	if s, ok := val.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(val)
+}
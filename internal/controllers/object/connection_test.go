@@ -0,0 +1,95 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	"aerf.io/provider-k8s/internal/celcheck"
+)
+
+func TestConnectionDetails(t *testing.T) {
+	secret := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "secret", "namespace": "default"},
+		"data":       map[string]any{"password": "c2VjcmV0"},
+	}}
+	service := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]any{"name": "svc", "namespace": "default"},
+		"spec":       map[string]any{"ports": []any{map[string]any{"port": int64(80)}}},
+	}}
+	loadBalancer := service.DeepCopy()
+	loadBalancer.Object["status"] = map[string]any{"loadBalancer": map[string]any{}}
+
+	tests := []struct {
+		name     string
+		details  []objv1alpha1.ConnectionDetail
+		limits   celcheck.Limits
+		observed *unstructured.Unstructured
+		want     managed.ConnectionDetails
+		wantErr  bool
+	}{
+		{
+			name:     "no connection details",
+			observed: service,
+		},
+		{
+			name:     "secret data is decoded",
+			details:  []objv1alpha1.ConnectionDetail{{Name: "password", FieldPath: "data.password"}},
+			observed: secret,
+			want:     managed.ConnectionDetails{"password": []byte("secret")},
+		},
+		{
+			name: "missing fields are skipped and non-strings are marshalled to JSON",
+			details: []objv1alpha1.ConnectionDetail{
+				{Name: "ip", FieldPath: "status.loadBalancer.ingress[0].ip"},
+				{Name: "port", FieldPath: "spec.ports[0].port"},
+				{Name: "ports", FieldPath: "spec.ports"},
+			},
+			observed: service,
+			want:     managed.ConnectionDetails{"port": []byte("80"), "ports": []byte(`[{"port":80}]`)},
+		},
+		{
+			name:     "CEL expression",
+			details:  []objv1alpha1.ConnectionDetail{{Name: "endpoint", CELExpression: `metadata.name + "." + metadata.namespace + ".svc"`}},
+			observed: service,
+			want:     managed.ConnectionDetails{"endpoint": []byte("svc.default.svc")},
+		},
+		{
+			name:     "CEL expression reading a missing field is skipped",
+			details:  []objv1alpha1.ConnectionDetail{{Name: "ip", CELExpression: `status.loadBalancer.ingress[0].ip`}},
+			observed: loadBalancer,
+			want:     managed.ConnectionDetails{},
+		},
+		{
+			name:     "failing CEL expression",
+			details:  []objv1alpha1.ConnectionDetail{{Name: "ip", CELExpression: `status.loadBalancer.ingress[0].ip`}},
+			observed: service,
+			wantErr:  true,
+		},
+		{
+			name:     "CEL expression exceeding the cost limit",
+			details:  []objv1alpha1.ConnectionDetail{{Name: "endpoint", CELExpression: `metadata.name + "." + metadata.namespace + ".svc"`}},
+			limits:   celcheck.Limits{Cost: 1},
+			observed: service,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := connectionDetails(tt.details, tt.limits, tt.observed)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,23 +2,23 @@ package object
 
 import (
 	"context"
-	"fmt"
 	"time"
 
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
-	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/cel/openapi/resolver"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -32,8 +32,17 @@ import (
 	"aerf.io/provider-k8s/internal/cacheregistry"
 	"aerf.io/provider-k8s/internal/celcheck"
 	"aerf.io/provider-k8s/internal/controllers/generic"
+	"aerf.io/provider-k8s/internal/metrics"
+	"aerf.io/provider-k8s/internal/objectindex"
+	"aerf.io/provider-k8s/internal/policy"
 	"aerf.io/provider-k8s/internal/restcfgutil"
 	"aerf.io/provider-k8s/internal/safecmp"
+	"aerf.io/provider-k8s/internal/schemacache"
+)
+
+const (
+	reasonDriftCorrected         event.Reason = "DriftCorrected"
+	reasonDeletionDeniedByPolicy event.Reason = "DeletionDeniedByPolicy"
 )
 
 const (
@@ -43,25 +52,49 @@ const (
 	errGetCreds     = "cannot get credentials"
 )
 
+// schemaCacheTTL is how long the OpenAPI schemas of remote objects are cached for.
+const schemaCacheTTL = 10 * time.Minute
+
+// Config configures the Object and ObjectSet controllers.
+type Config struct {
+	// MaxDiffSize is the maximum size in bytes of the drift diffs kept in statuses and events. Non-positive means no limit.
+	MaxDiffSize int
+	// CELLimits are the default limits of the evaluation of CEL readiness expressions.
+	CELLimits celcheck.Limits
+}
+
 // Setup adds a controller that reconciles Object managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options, registry *cacheregistry.Registry) error {
+func Setup(mgr ctrl.Manager, o controller.Options, registry *cacheregistry.Registry, cfg Config) error {
 	name := managed.ControllerName(objv1alpha1.ObjectGroupKind)
+	managementPoliciesEnabled := o.Features.Enabled(feature.EnableBetaManagementPolicies)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 
 	opts := []managed.ReconcilerOption{
 		managed.WithExternalConnecter(&connector{
-			client:       mgr.GetClient(),
-			usageTracker: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			logger:       o.Logger,
-			registry:     registry,
+			client:                    mgr.GetClient(),
+			usageTracker:              resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			logger:                    o.Logger,
+			registry:                  registry,
+			schemas:                   schemacache.New(schemaCacheTTL),
+			recorder:                  recorder,
+			config:                    cfg,
+			managementPoliciesEnabled: managementPoliciesEnabled,
 		}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 		managed.WithCreationGracePeriod(3 * time.Second),
 	}
+	if managementPoliciesEnabled {
+		opts = append(opts, managed.WithManagementPolicies())
+	}
 
 	r := managed.NewReconciler(mgr, resource.ManagedKind(objv1alpha1.ObjectGroupVersionKind), opts...)
 
+	if err := objectindex.Setup(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		return err
+	}
+
 	objectController, err := ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
@@ -87,10 +120,14 @@ func Setup(mgr ctrl.Manager, o controller.Options, registry *cacheregistry.Regis
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
-	client       client.Client
-	usageTracker resource.Tracker
-	logger       logging.Logger
-	registry     *cacheregistry.Registry
+	client                    client.Client
+	usageTracker              resource.Tracker
+	logger                    logging.Logger
+	registry                  *cacheregistry.Registry
+	schemas                   *schemacache.Cache
+	recorder                  event.Recorder
+	config                    Config
+	managementPoliciesEnabled bool
 }
 
 // Connect typically produces an ExternalClient by:
@@ -104,12 +141,25 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.New(errNotObject)
 	}
 
+	ext, err := c.newExternal(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	ext.policy = managed.NewManagementPoliciesResolver(c.managementPoliciesEnabled, cr.GetManagementPolicies(), cr.GetDeletionPolicy())
+	ext.applyOpts = cr.Spec.Apply
+	ext.updateStrategy = cr.Spec.UpdateStrategy
+
+	return generic.NewExternalForType[*objv1alpha1.Object](ext, errors.New(errNotObject)), nil
+}
+
+// newExternal builds the client for the remote cluster pointed to by the managed resource's ProviderConfig.
+func (c *connector) newExternal(ctx context.Context, mg resource.Managed) (*external, error) {
 	if err := c.usageTracker.Track(ctx, mg); err != nil {
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
 	pc := &apisv1alpha1.ProviderConfig{}
-	if err := c.client.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+	if err := c.client.Get(ctx, types.NamespacedName{Name: mg.GetProviderConfigReference().Name}, pc); err != nil {
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
@@ -123,13 +173,26 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, err
 	}
 
-	return generic.NewExternalForType[*objv1alpha1.Object](&external{
-		localCli:      c.client,
-		remoteCli:     remoteCli,
-		log:           c.logger,
-		registry:      c.registry,
-		remoteRestCfg: rc,
-	}, errors.New(errNotObject)), nil
+	return &external{
+		localCli:       c.client,
+		remoteCli:      metrics.InstrumentClient(remoteCli, rc.Host),
+		providerConfig: pc.GetName(),
+		pcPolicy:       pc.Spec.Policy,
+		log:            c.logger,
+		registry:       c.registry,
+		recorder:       c.recorder,
+		config:         c.config,
+		remoteRestCfg:  rc,
+		schemas:        c.schemas.For(rc),
+		policy:         managed.NewManagementPoliciesResolver(false, mg.GetManagementPolicies(), mg.GetDeletionPolicy()),
+	}, nil
+}
+
+// cacheRegistry keeps the informer caches of the remote objects, see cacheregistry.Registry.
+type cacheRegistry interface {
+	Get(ctx context.Context, restCfg *rest.Config, obj *unstructured.Unstructured) (bool, error)
+	RegisterCacheFromRestConfig(restCfg *rest.Config, gvk schema.GroupVersionKind, nameNs, parentNameNs types.NamespacedName) error
+	StopAndRemove(restCfg *rest.Config, gvk schema.GroupVersionKind, childNameNs types.NamespacedName) error
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -138,21 +201,65 @@ type external struct {
 	localCli      client.Client
 	remoteCli     client.Client
 	log           logging.Logger
-	registry      *cacheregistry.Registry
+	registry      cacheRegistry
+	recorder      event.Recorder
+	config        Config
 	remoteRestCfg *rest.Config
+	// schemas resolve the OpenAPI schemas of remote objects, typing `self` in CEL readiness expressions.
+	schemas resolver.SchemaResolver
+	// providerConfig is the name of the ProviderConfig pointing to the remote cluster.
+	providerConfig string
+	// pcPolicy restricts the remote objects managed via the ProviderConfig.
+	pcPolicy apisv1alpha1.Policy
+	// policy tells which actions are allowed by the managed resource's management policies.
+	policy managed.ManagementPoliciesChecker
+	// applyOpts configure the server-side apply of remote objects.
+	applyOpts objv1alpha1.Apply
+	// updateStrategy defines how remote objects are created and updated.
+	updateStrategy objv1alpha1.UpdateStrategy
+	// drift is the diff of the drifted remote object found by Observe, reported once Update corrects it.
+	drift string
 }
 
 func (e *external) Observe(ctx context.Context, cr *objv1alpha1.Object) (managed.ExternalObservation, error) {
 	log := e.loggerFor(cr)
 	log.Debug("Observing", "reconciledObject", cr)
 
-	desired, err := cr.GetDesired()
+	var desired *unstructured.Unstructured
+	var err error
+	if meta.WasDeleted(cr) {
+		// references are not resolved during deletion, their sources are likely being deleted as well
+		desired, err = cr.GetDesired()
+	} else {
+		desired, err = e.desiredFor(ctx, cr)
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	cr.Status.AtProvider.Host = e.remoteRestCfg.Host
+	owner, err := e.remoteObjectOwner(ctx, cr, desired)
 	if err != nil {
 		return managed.ExternalObservation{}, err
 	}
+	if owner != "" {
+		if meta.WasDeleted(cr) {
+			// the remote object is left to its owner, only this Object is deleted
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Errorf("remote object is already managed by %s", owner)
+	}
 
 	if meta.WasDeleted(cr) {
-		err = e.registry.StopAndRemove(e.remoteRestCfg, desired.GroupVersionKind(), client.ObjectKeyFromObject(desired))
+		if err := e.registry.StopAndRemove(e.remoteRestCfg, desired.GroupVersionKind(), client.ObjectKeyFromObject(desired)); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if err := e.checkPolicy(desired); err != nil {
+			// the remote object is left intact, so that the Object can still be deleted. Reporting it as gone keeps
+			// Delete from being called.
+			e.recorder.Event(cr, event.Warning(reasonDeletionDeniedByPolicy, errors.Wrap(err, "leaving the remote object in place")))
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
 	} else {
 		err = e.registry.RegisterCacheFromRestConfig(e.remoteRestCfg, desired.GroupVersionKind(), client.ObjectKeyFromObject(desired), client.ObjectKeyFromObject(cr))
 	}
@@ -161,20 +268,59 @@ func (e *external) Observe(ctx context.Context, cr *objv1alpha1.Object) (managed
 	}
 
 	observed := desired.DeepCopy()
-	err = e.localCli.Get(ctx, types.NamespacedName{
-		Namespace: desired.GetNamespace(),
-		Name:      desired.GetName(),
-	}, observed)
+	err = e.Get(ctx, observed)
 	if apierrors.IsNotFound(err) {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	} else if err != nil {
 		return managed.ExternalObservation{}, err
 	}
 
-	if err := e.ApplyDryRun(ctx, desired); err != nil {
+	if meta.WasDeleted(cr) {
+		return e.observeDeletion(cr, observed), nil
+	}
+
+	conn, err := connectionDetails(cr.Spec.ConnectionDetails, e.config.CELLimits, observed)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	if e.policy.ShouldOnlyObserve() {
+		// the remote object is owned by someone else, so we only mirror it without even dry-running the apply
+		return managed.ExternalObservation{
+			ResourceExists:    true,
+			ResourceUpToDate:  true,
+			ConnectionDetails: conn,
+		}, errors.Wrap(e.setObserved(cr, observed), "failed to derive object status from the observed remote object")
+	}
+
+	updatable, err := e.dryRunUpdate(ctx, desired)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if !updatable {
+		// the remote object is never updated after creation, so its drift doesn't matter
+		return managed.ExternalObservation{
+			ResourceExists:    true,
+			ResourceUpToDate:  true,
+			ConnectionDetails: conn,
+		}, errors.Wrap(e.setObserved(cr, observed), "failed to derive object status from the observed remote object")
+	}
+
+	comparedObserved, comparedDesired, err := e.ownedFields(observed, desired)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	comparedObserved, comparedDesired, err = withoutIgnoredFields(cr.Spec.Drift.Ignore, comparedObserved, comparedDesired)
+	if err != nil {
 		return managed.ExternalObservation{}, err
 	}
 
+	upToDate := !e.hasDrifted(comparedObserved, comparedDesired)
+	if !upToDate {
+		metrics.DriftDetected.With(metrics.ObjectLabels(desired.GroupVersionKind(), e.providerConfig)).Inc()
+		e.drift = safecmp.Truncate(safecmp.DiffUnstructured(comparedObserved, comparedDesired), e.config.MaxDiffSize)
+	}
+
 	return managed.ExternalObservation{
 		// Return false when the external resource does not exist. This lets
 		// the managed resource reconciler know that it needs to call Create to
@@ -184,8 +330,9 @@ func (e *external) Observe(ctx context.Context, cr *objv1alpha1.Object) (managed
 		// Return false when the external resource exists, but it not up to date
 		// with the desired managed resource state. This lets the managed
 		// resource reconciler know that it needs to call Update.
-		ResourceUpToDate: !e.hasDrifted(observed, desired),
-		Diff:             safecmp.DiffUnstructured(observed, desired),
+		ResourceUpToDate:  upToDate,
+		Diff:              e.drift,
+		ConnectionDetails: conn,
 	}, errors.Wrap(e.setObserved(cr, observed), "failed to derive object status from the observed remote object")
 }
 
@@ -193,36 +340,68 @@ func (e *external) Create(ctx context.Context, cr *objv1alpha1.Object) (managed.
 	log := e.loggerFor(cr)
 	log.Debug("Creating")
 
-	desired, err := cr.GetDesired()
+	if !e.policy.ShouldCreate() {
+		log.Debug("Skipping create due to managementPolicies")
+		return managed.ExternalCreation{}, nil
+	}
+
+	desired, err := e.desiredFor(ctx, cr)
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
-	if err := e.Apply(ctx, desired); err != nil {
+	if err := e.create(ctx, desired); err != nil {
 		return managed.ExternalCreation{}, err
 	}
 
 	log.Debug("Created object", "object", desired)
 
-	return managed.ExternalCreation{}, errors.Wrap(e.setObserved(cr, desired), "failed to derive object status from the observed remote object")
+	conn, err := connectionDetails(cr.Spec.ConnectionDetails, e.config.CELLimits, desired)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	return managed.ExternalCreation{ConnectionDetails: conn}, errors.Wrap(e.setObserved(cr, desired), "failed to derive object status from the observed remote object")
 }
 
 func (e *external) Update(ctx context.Context, cr *objv1alpha1.Object) (managed.ExternalUpdate, error) {
-	e.loggerFor(cr).Debug("Updating")
+	log := e.loggerFor(cr)
+	log.Debug("Updating")
 
-	desired, err := cr.GetDesired()
+	if !e.policy.ShouldUpdate() {
+		log.Debug("Skipping update due to managementPolicies")
+		return managed.ExternalUpdate{}, nil
+	}
+
+	desired, err := e.desiredFor(ctx, cr)
 	if err != nil {
 		return managed.ExternalUpdate{}, err
 	}
 
-	if err := e.Apply(ctx, desired); err != nil {
+	if err := e.update(ctx, desired); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if e.drift != "" {
+		metrics.DriftCorrected.With(metrics.ObjectLabels(desired.GroupVersionKind(), e.providerConfig)).Inc()
+		cr.Status.AtProvider.LastDrift = &objv1alpha1.CorrectedDrift{Time: metav1.Now(), Diff: e.drift}
+		e.recorder.Event(cr, event.Normal(reasonDriftCorrected, "Corrected the drift of the remote object:\n"+e.drift))
+	}
+
+	conn, err := connectionDetails(cr.Spec.ConnectionDetails, e.config.CELLimits, desired)
+	if err != nil {
 		return managed.ExternalUpdate{}, err
 	}
 
-	return managed.ExternalUpdate{}, e.updateConditionFromObserved(cr, desired)
+	return managed.ExternalUpdate{ConnectionDetails: conn}, e.updateConditionFromObserved(cr, desired)
 }
 
 func (e *external) Delete(ctx context.Context, cr *objv1alpha1.Object) error {
-	e.loggerFor(cr).Debug("Deleting")
+	log := e.loggerFor(cr)
+	log.Debug("Deleting")
+
+	if !e.policy.ShouldDelete() {
+		log.Debug("Skipping delete due to managementPolicies")
+		return nil
+	}
 
 	desired, err := cr.GetDesired()
 	if err != nil {
@@ -233,7 +412,31 @@ func (e *external) Delete(ctx context.Context, cr *objv1alpha1.Object) error {
 		return errors.Wrapf(err, "failed to stop the cache for cluster with host url %q, object gvk %q, name/ns %q", e.remoteRestCfg.Host, desired.GroupVersionKind(), client.ObjectKeyFromObject(desired))
 	}
 
-	return errors.Wrap(client.IgnoreNotFound(e.remoteCli.Delete(ctx, desired)), "failed to delete external object")
+	opts := []client.DeleteOption{}
+	if policy := cr.Spec.Deletion.PropagationPolicy; policy != nil {
+		opts = append(opts, client.PropagationPolicy(*policy))
+	}
+	if gracePeriod := cr.Spec.Deletion.GracePeriodSeconds; gracePeriod != nil {
+		opts = append(opts, client.GracePeriodSeconds(*gracePeriod))
+	}
+
+	return errors.Wrap(client.IgnoreNotFound(e.remoteCli.Delete(ctx, desired, opts...)), "failed to delete external object")
+}
+
+// observeDeletion reports whether the remote object of the Object being deleted should still be considered existing.
+// Once the deletion of the remote object has been requested it's reported as gone, unless the Object waits until it's really gone.
+func (e *external) observeDeletion(cr *objv1alpha1.Object, observed *unstructured.Unstructured) managed.ExternalObservation {
+	if observed.GetDeletionTimestamp() == nil || !cr.Spec.Deletion.WaitUntilGone {
+		return managed.ExternalObservation{ResourceExists: observed.GetDeletionTimestamp() == nil}
+	}
+
+	if timeout := cr.Spec.Deletion.Timeout; timeout != nil && time.Since(cr.GetDeletionTimestamp().Time) > timeout.Duration {
+		e.loggerFor(cr).Info("Timed out waiting for the remote object to be deleted, removing the finalizer anyway", "timeout", timeout.Duration, "finalizers", observed.GetFinalizers())
+		return managed.ExternalObservation{ResourceExists: false}
+	}
+
+	cr.SetConditions(objv1alpha1.WaitingForRemoteDeletion(observed.GetFinalizers()))
+	return managed.ExternalObservation{ResourceExists: true}
 }
 
 func (e *external) loggerFor(obj client.Object) logging.Logger {
@@ -241,60 +444,54 @@ func (e *external) loggerFor(obj client.Object) logging.Logger {
 	return e.log.WithValues("name", obj.GetName(), "namespace", obj.GetNamespace(), "kind", gvk.Kind, "group", gvk.Group, "version", gvk.Version)
 }
 
-func (e *external) Apply(ctx context.Context, obj client.Object, opts ...client.PatchOption) error {
-	patchOpts := append(opts, client.ForceOwnership, client.FieldOwner("provider-k8s")) // nolint:gocritic // it's deliberate
-	return e.remoteCli.Patch(ctx, obj, client.Apply, patchOpts...)
+// Get reads the remote object into obj. It's served from the informer cache registered for the object once it's synced,
+// so that steady-state polls don't hit the remote API server.
+func (e *external) Get(ctx context.Context, obj *unstructured.Unstructured) error {
+	if cached, err := e.registry.Get(ctx, e.remoteRestCfg, obj); cached {
+		return err
+	}
+	return e.remoteCli.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+}
+
+// checkPolicy returns an error if the ProviderConfig's policy doesn't allow managing the remote object.
+func (e *external) checkPolicy(obj *unstructured.Unstructured) error {
+	return policy.Check(e.pcPolicy, obj, e.config.CELLimits)
+}
+
+// Apply server-side applies obj according to the apply options. Without force, conflicting fields are taken over only if
+// all of them are allowed to be forced, otherwise the conflicts are returned as an error.
+func (e *external) Apply(ctx context.Context, obj client.Object) error {
+	patchOpts := []client.PatchOption{client.FieldOwner(fieldManager(e.applyOpts))}
+	if shouldForce(e.applyOpts) {
+		return e.remoteCli.Patch(ctx, obj, client.Apply, append(patchOpts, client.ForceOwnership)...)
+	}
+
+	err := e.remoteCli.Patch(ctx, obj, client.Apply, patchOpts...)
+	conflicts := fieldConflictsOf(err)
+	if len(conflicts) == 0 {
+		return err
+	}
+	forceable := areForceable(conflicts, e.applyOpts.ForceFields)
+	metrics.ApplyConflicts.With(metrics.ConflictLabels(obj.GetObjectKind().GroupVersionKind(), e.providerConfig, forceable)).Inc()
+	if !forceable {
+		return conflictError(conflicts)
+	}
+	e.log.Debug("Forcing the ownership of conflicting fields", "conflicts", describeConflicts(conflicts))
+	return e.remoteCli.Patch(ctx, obj, client.Apply, append(patchOpts, client.ForceOwnership)...)
 }
 
+// ApplyDryRun always forces the ownership, so that the result shows the object as it would be after the apply even if
+// some fields are owned by other field managers. Such conflicts are reported by Apply.
 func (e *external) ApplyDryRun(ctx context.Context, obj client.Object) error {
-	return e.Apply(ctx, obj, client.DryRunAll)
+	return e.remoteCli.Patch(ctx, obj, client.Apply, client.DryRunAll, client.ForceOwnership, client.FieldOwner(fieldManager(e.applyOpts)))
 }
 
 func (e *external) updateConditionFromObserved(obj *objv1alpha1.Object, observed *unstructured.Unstructured) error {
-	log := e.loggerFor(obj)
-	switch obj.Spec.Readiness.Policy {
-	case objv1alpha1.ReadinessPolicyDeriveFromObject:
-		conditioned := xpv1.ConditionedStatus{}
-		err := fieldpath.Pave(observed.Object).GetValueInto("status", &conditioned)
-		if err != nil {
-			log.Debug("Got error while getting conditions from observed object, setting it as Unavailable", "error", err, "observed", observed)
-			obj.SetConditions(xpv1.Unavailable().WithMessage("Got error while getting conditions from observed object"))
-			return errors.Wrap(err, "failed to get conditions from observed object")
-		}
-		if status := conditioned.GetCondition(xpv1.TypeReady).Status; status != corev1.ConditionTrue {
-			log.Debug("Observed object is not ready, setting it as Unavailable", "status", status, "observed", observed)
-			obj.SetConditions(xpv1.Unavailable().WithMessage(fmt.Sprintf("Observed object's condition with type %q is %q but should be %q", xpv1.TypeReady, status, corev1.ConditionTrue)))
-			return nil
-		}
-		obsrvdGenStatus := objv1alpha1.StatusWithObservedGeneration{}
-		if err := fieldpath.Pave(observed.Object).GetValueInto("status", &obsrvdGenStatus); err == nil {
-			if observed.GetGeneration() != obsrvdGenStatus.ObservedGeneration {
-				log.Debug("Observed object is not ready, setting it as Unavailable", "status", obsrvdGenStatus, "observed", observed)
-				obj.SetConditions(xpv1.Unavailable().WithMessage("Observed object's status.observedGeneration is not equal to metadata.generation"))
-				return nil
-			}
-		}
-
-		obj.SetConditions(xpv1.Available())
-	case objv1alpha1.ReadinessPolicySuccessfulCreate, "":
-		obj.SetConditions(xpv1.Available())
-	case objv1alpha1.ReadinessPolicyUseCELExpression:
-		ready, err := celcheck.Eval(obj.Spec.Readiness.CELExpression, observed.UnstructuredContent())
-		if err != nil {
-			return errors.Wrap(err, "failed to run CEL expression on observed object")
-		}
-		cond := xpv1.Unavailable()
-		if ready {
-			cond = xpv1.Available()
-		}
+	cond, err := readinessCondition(e.loggerFor(obj), obj.Spec.Readiness, e.config.CELLimits, e.schemas, observed)
+	if cond.Type != "" {
 		obj.SetConditions(cond)
-
-		return nil
-	default:
-		// should never happen
-		return errors.Errorf("unknown readiness policy %q", obj.Spec.Readiness.Policy)
 	}
-	return nil
+	return err
 }
 
 func (e *external) setObserved(obj *objv1alpha1.Object, observed *unstructured.Unstructured) error {
@@ -303,6 +500,10 @@ func (e *external) setObserved(obj *objv1alpha1.Object, observed *unstructured.U
 		return errors.Wrap(err, "failed to marshal")
 	}
 
+	if obj.Status.AtProvider.Fields, err = projectStatus(e.loggerFor(obj), obj.Spec.StatusProjections, e.config.CELLimits, observed); err != nil {
+		return err
+	}
+
 	if err := e.updateConditionFromObserved(obj, observed); err != nil {
 		return err
 	}
@@ -0,0 +1,197 @@
+package object
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+)
+
+func TestObserveDeletion(t *testing.T) {
+	deletedAt := metav1.NewTime(time.Now().Add(-time.Minute))
+	object := func(deletion objv1alpha1.Deletion) *objv1alpha1.Object {
+		return &objv1alpha1.Object{
+			ObjectMeta: metav1.ObjectMeta{Name: "obj", DeletionTimestamp: &deletedAt},
+			Spec:       objv1alpha1.ObjectSpec{Deletion: deletion},
+		}
+	}
+	observed := func(deleting bool, finalizers ...string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("ConfigMap")
+		u.SetName("cm")
+		u.SetFinalizers(finalizers)
+		if deleting {
+			u.SetDeletionTimestamp(&deletedAt)
+		}
+		return u
+	}
+
+	tests := []struct {
+		name       string
+		cr         *objv1alpha1.Object
+		observed   *unstructured.Unstructured
+		wantExists bool
+		wantCond   *xpv1.Condition
+	}{
+		{
+			name:       "deletion not started",
+			cr:         object(objv1alpha1.Deletion{WaitUntilGone: true}),
+			observed:   observed(false),
+			wantExists: true,
+		},
+		{
+			name:     "deleting without waiting",
+			cr:       object(objv1alpha1.Deletion{}),
+			observed: observed(true, "example.com/cleanup"),
+		},
+		{
+			name:       "deleting and waiting",
+			cr:         object(objv1alpha1.Deletion{WaitUntilGone: true}),
+			observed:   observed(true, "example.com/cleanup"),
+			wantExists: true,
+			wantCond:   ptr.To(objv1alpha1.WaitingForRemoteDeletion([]string{"example.com/cleanup"})),
+		},
+		{
+			name:       "waiting within the timeout",
+			cr:         object(objv1alpha1.Deletion{WaitUntilGone: true, Timeout: &metav1.Duration{Duration: time.Hour}}),
+			observed:   observed(true),
+			wantExists: true,
+			wantCond:   ptr.To(objv1alpha1.WaitingForRemoteDeletion(nil)),
+		},
+		{
+			name:     "timed out",
+			cr:       object(objv1alpha1.Deletion{WaitUntilGone: true, Timeout: &metav1.Duration{Duration: time.Second}}),
+			observed: observed(true, "example.com/cleanup"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &external{log: logging.NewNopLogger()}
+
+			got := e.observeDeletion(tt.cr, tt.observed)
+			require.Equal(t, managed.ExternalObservation{ResourceExists: tt.wantExists}, got)
+			cond := tt.cr.GetCondition(objv1alpha1.TypeRemoteDeletion)
+			if tt.wantCond == nil {
+				require.Equal(t, corev1.ConditionUnknown, cond.Status)
+				return
+			}
+			require.True(t, cond.Equal(*tt.wantCond), cond)
+		})
+	}
+}
+
+func TestDeleteOptions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	tests := []struct {
+		name     string
+		deletion objv1alpha1.Deletion
+		want     client.DeleteOptions
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name: "propagation policy and grace period",
+			deletion: objv1alpha1.Deletion{
+				PropagationPolicy:  ptr.To(metav1.DeletePropagationForeground),
+				GracePeriodSeconds: ptr.To[int64](30),
+			},
+			want: client.DeleteOptions{
+				PropagationPolicy:  ptr.To(metav1.DeletePropagationForeground),
+				GracePeriodSeconds: ptr.To[int64](30),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got client.DeleteOptions
+			remoteCli := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+						got.ApplyOptions(opts)
+						return c.Delete(ctx, obj, opts...)
+					},
+				}).Build()
+			e := &external{
+				remoteCli:     remoteCli,
+				log:           logging.NewNopLogger(),
+				registry:      &registry{},
+				remoteRestCfg: &rest.Config{Host: "https://remote.example.com"},
+				policy:        managed.NewManagementPoliciesResolver(false, nil, xpv1.DeletionDelete),
+			}
+			cr := &objv1alpha1.Object{Spec: objv1alpha1.ObjectSpec{
+				ForProvider: objv1alpha1.ObjectParameters{Manifest: configMapManifest("default", "cm", 0).Manifest},
+				Deletion:    tt.deletion,
+			}}
+
+			require.NoError(t, e.Delete(context.Background(), cr))
+			require.Equal(t, tt.want, got)
+			require.Equal(t, []string{"ConfigMap default/cm"}, e.registry.(*registry).stopped) //nolint:forcetypeassert // set above
+		})
+	}
+}
+
+func TestObserveOnly(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	var writes []string
+	remoteCli := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"},
+			Data:       map[string]string{"owned": "elsewhere"},
+		}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				writes = append(writes, "patch")
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				writes = append(writes, "update")
+				return c.Update(ctx, obj, opts...)
+			},
+		}).Build()
+	e := &external{
+		localCli:      newLocalClient(t),
+		remoteCli:     remoteCli,
+		log:           logging.NewNopLogger(),
+		registry:      &registry{},
+		recorder:      &recorder{},
+		remoteRestCfg: &rest.Config{Host: "https://remote.example.com"},
+		policy:        managed.NewManagementPoliciesResolver(true, xpv1.ManagementPolicies{xpv1.ManagementActionObserve}, xpv1.DeletionOrphan),
+	}
+	cr := &objv1alpha1.Object{
+		ObjectMeta: metav1.ObjectMeta{Name: "obj"},
+		Spec: objv1alpha1.ObjectSpec{
+			ForProvider: objv1alpha1.ObjectParameters{Manifest: configMapManifest("default", "cm", 0).Manifest},
+		},
+	}
+
+	obs, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	require.Equal(t, managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, obs)
+	require.Empty(t, writes)
+
+	observed := &corev1.ConfigMap{}
+	require.NoError(t, json.Unmarshal(cr.Status.AtProvider.Manifest.Raw, observed))
+	require.Equal(t, map[string]string{"owned": "elsewhere"}, observed.Data)
+}
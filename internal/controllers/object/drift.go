@@ -3,6 +3,10 @@ package object
 import (
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	"aerf.io/provider-k8s/internal/managedfields"
 )
 
 // content of this file has been heavily influenced (copied even) from github.com/fluxcd/pkg/ssa library
@@ -39,3 +43,40 @@ func prepareObjectForDiff(object *unstructured.Unstructured) *unstructured.Unstr
 	unstructured.RemoveNestedField(deepCopy.Object, "status")
 	return deepCopy
 }
+
+// ownedFields returns the observed and dry-run objects limited to the fields owned by the provider's field manager, either
+// before or after the apply. This way fields set by other actors, e.g. the `deployment.kubernetes.io/revision` annotation,
+// are not considered a drift, while fields removed from the manifest still are.
+// The objects are returned as they are if the field manager doesn't own any fields, e.g. when they're not server-side applied.
+func (e *external) ownedFields(observed, dryRun *unstructured.Unstructured) (*unstructured.Unstructured, *unstructured.Unstructured, error) {
+	if e.updateStrategy != objv1alpha1.UpdateStrategyServerSideApply && e.updateStrategy != "" {
+		return observed, dryRun, nil
+	}
+
+	manager := fieldManager(e.applyOpts)
+	ownedAfter, err := managedfields.OwnedBy(dryRun, manager)
+	if err != nil {
+		return nil, nil, err
+	}
+	ownedBefore, err := managedfields.OwnedBy(observed, manager)
+	if err != nil {
+		return nil, nil, err
+	}
+	owned := ownedAfter.Union(ownedBefore)
+	if owned.Empty() {
+		return observed, dryRun, nil
+	}
+	return onlyFields(observed, owned), onlyFields(dryRun, owned), nil
+}
+
+// onlyFields returns a copy of obj limited to the given fields. The identity of obj is always kept, so that the diff stays
+// readable and hasDrifted can tell whether the object exists.
+func onlyFields(obj *unstructured.Unstructured, fields *fieldpath.Set) *unstructured.Unstructured {
+	filtered := &unstructured.Unstructured{Object: managedfields.Filter(obj.DeepCopy().Object, fields)}
+	filtered.SetAPIVersion(obj.GetAPIVersion())
+	filtered.SetKind(obj.GetKind())
+	filtered.SetName(obj.GetName())
+	filtered.SetNamespace(obj.GetNamespace())
+	filtered.SetResourceVersion(obj.GetResourceVersion())
+	return filtered
+}
@@ -0,0 +1,75 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+)
+
+func TestOwnedFieldsDrift(t *testing.T) {
+	const owned = `{"f:metadata":{"f:labels":{"f:app":{}}},"f:data":{"f:key":{}}}`
+	newCM := func(labels map[string]string, data map[string]any, fields map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"data":       data,
+		}}
+		obj.SetName("cm")
+		obj.SetNamespace("default")
+		obj.SetResourceVersion("1")
+		obj.SetLabels(labels)
+		entries := make([]metav1.ManagedFieldsEntry, 0, len(fields))
+		for manager, raw := range fields {
+			entries = append(entries, metav1.ManagedFieldsEntry{Manager: manager, Operation: metav1.ManagedFieldsOperationApply, FieldsV1: &metav1.FieldsV1{Raw: []byte(raw)}})
+		}
+		obj.SetManagedFields(entries)
+		return obj
+	}
+
+	tests := []struct {
+		name        string
+		strategy    objv1alpha1.UpdateStrategy
+		observed    *unstructured.Unstructured
+		dryRun      *unstructured.Unstructured
+		wantDrifted bool
+	}{
+		{
+			name: "fields of other managers are not a drift",
+			observed: newCM(map[string]string{"app": "cm", "other": "label"}, map[string]any{"key": "value", "other": "value"},
+				map[string]string{"provider-k8s": owned, "kubectl": `{"f:metadata":{"f:labels":{"f:other":{}}},"f:data":{"f:other":{}}}`}),
+			dryRun: newCM(map[string]string{"app": "cm", "other": "label"}, map[string]any{"key": "value", "other": "changed"},
+				map[string]string{"provider-k8s": owned, "kubectl": `{"f:metadata":{"f:labels":{"f:other":{}}},"f:data":{"f:other":{}}}`}),
+		},
+		{
+			name:        "changes of owned fields are a drift",
+			observed:    newCM(map[string]string{"app": "cm"}, map[string]any{"key": "changed"}, map[string]string{"provider-k8s": owned}),
+			dryRun:      newCM(map[string]string{"app": "cm"}, map[string]any{"key": "value"}, map[string]string{"provider-k8s": owned}),
+			wantDrifted: true,
+		},
+		{
+			name:        "fields removed from the manifest are a drift",
+			observed:    newCM(map[string]string{"app": "cm"}, map[string]any{"key": "value"}, map[string]string{"provider-k8s": owned}),
+			dryRun:      newCM(map[string]string{"app": "cm"}, map[string]any{}, map[string]string{"provider-k8s": `{"f:metadata":{"f:labels":{"f:app":{}}}}`}),
+			wantDrifted: true,
+		},
+		{
+			name:        "everything is compared without server-side apply",
+			strategy:    objv1alpha1.UpdateStrategyMergePatch,
+			observed:    newCM(nil, map[string]any{"key": "value", "other": "value"}, map[string]string{"provider-k8s": owned}),
+			dryRun:      newCM(nil, map[string]any{"key": "value", "other": "changed"}, map[string]string{"provider-k8s": owned}),
+			wantDrifted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &external{updateStrategy: tt.strategy}
+			observed, dryRun, err := e.ownedFields(tt.observed, tt.dryRun)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDrifted, e.hasDrifted(observed, dryRun))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package object
+
+import (
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	smdfieldpath "sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	"aerf.io/provider-k8s/internal/managedfields"
+)
+
+// withoutIgnoredFields returns copies of the observed and dry-run objects without the fields whose differences should be ignored.
+func withoutIgnoredFields(ignore objv1alpha1.DriftIgnore, observed, dryRun *unstructured.Unstructured) (*unstructured.Unstructured, *unstructured.Unstructured, error) {
+	paths := make([]string, 0, len(ignore.JSONPointers)+len(ignore.FieldPaths))
+	for _, ptr := range ignore.JSONPointers {
+		path, err := fieldPathFromJSONPointer(ptr)
+		if err != nil {
+			return nil, nil, err
+		}
+		paths = append(paths, path)
+	}
+	paths = append(paths, ignore.FieldPaths...)
+
+	var owned *smdfieldpath.Set
+	if len(ignore.ManagedFieldsManagers) > 0 {
+		var err error
+		if owned, err = managedfields.OwnedBy(observed, ignore.ManagedFieldsManagers...); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	strip := func(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		stripped := obj.DeepCopy()
+		paved := fieldpath.Pave(stripped.Object)
+		for _, path := range paths {
+			expanded, err := paved.ExpandWildcards(path)
+			if fieldpath.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot expand ignored field path %q", path)
+			}
+			// deleting from the end keeps the indexes of the preceding array elements valid
+			for i := len(expanded) - 1; i >= 0; i-- {
+				if err := paved.DeleteField(expanded[i]); err != nil {
+					return nil, errors.Wrapf(err, "cannot ignore field path %q", expanded[i])
+				}
+			}
+		}
+		stripped.Object = paved.UnstructuredContent()
+		if owned != nil {
+			managedfields.Remove(stripped.Object, owned)
+		}
+		return stripped, nil
+	}
+
+	strippedObserved, err := strip(observed)
+	if err != nil {
+		return nil, nil, err
+	}
+	strippedDryRun, err := strip(dryRun)
+	if err != nil {
+		return nil, nil, err
+	}
+	return strippedObserved, strippedDryRun, nil
+}
+
+// fieldPathFromJSONPointer converts a RFC 6901 JSON pointer to a field path understood by fieldpath.Paved.
+func fieldPathFromJSONPointer(ptr string) (string, error) {
+	if !strings.HasPrefix(ptr, "/") || ptr == "/" {
+		return "", errors.Errorf("invalid JSON pointer %q, it must start with / and point to a field", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	segments := make(fieldpath.Segments, 0, len(tokens))
+	for _, token := range tokens {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		segments = append(segments, fieldpath.FieldOrIndex(token))
+	}
+	return segments.String(), nil
+}
@@ -0,0 +1,85 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+)
+
+func TestWithoutIgnoredFields(t *testing.T) {
+	newDeploy := func(replicas int64, revision, image string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name":        "deploy",
+				"annotations": map[string]any{"example.com/revision": revision},
+			},
+			"spec": map[string]any{
+				"replicas": replicas,
+				"template": map[string]any{"spec": map[string]any{"containers": []any{
+					map[string]any{"name": "nginx", "image": image},
+					map[string]any{"name": "sidecar", "image": image},
+				}}},
+			},
+		}}
+		obj.SetResourceVersion("1")
+		obj.SetManagedFields([]metav1.ManagedFieldsEntry{{
+			Manager:  "kube-controller-manager",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)},
+		}})
+		return obj
+	}
+	observed := newDeploy(5, "2", "nginx:1.25")
+	dryRun := newDeploy(1, "1", "nginx:1.26")
+
+	tests := []struct {
+		name        string
+		ignore      objv1alpha1.DriftIgnore
+		wantDrifted bool
+		wantErr     bool
+	}{
+		{
+			name:        "nothing ignored",
+			wantDrifted: true,
+		},
+		{
+			name: "everything ignored",
+			ignore: objv1alpha1.DriftIgnore{
+				JSONPointers:          []string{"/metadata/annotations/example.com~1revision"},
+				FieldPaths:            []string{"spec.template.spec.containers[*].image"},
+				ManagedFieldsManagers: []string{"kube-controller-manager"},
+			},
+		},
+		{
+			name: "only some fields ignored",
+			ignore: objv1alpha1.DriftIgnore{
+				JSONPointers: []string{"/spec/replicas", "/spec/template/spec/containers/0/image"},
+				FieldPaths:   []string{"metadata.annotations[example.com/revision]"},
+			},
+			wantDrifted: true,
+		},
+		{
+			name:    "invalid JSON pointer",
+			ignore:  objv1alpha1.DriftIgnore{JSONPointers: []string{"spec/replicas"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotObserved, gotDryRun, err := withoutIgnoredFields(tt.ignore, observed, dryRun)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDrifted, (&external{}).hasDrifted(gotObserved, gotDryRun))
+			// the inputs are left intact
+			require.Equal(t, int64(5), observed.Object["spec"].(map[string]any)["replicas"])
+		})
+	}
+}
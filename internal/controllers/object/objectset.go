@@ -0,0 +1,404 @@
+package object
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+	"aerf.io/provider-k8s/internal/cacheregistry"
+	"aerf.io/provider-k8s/internal/controllers/generic"
+	"aerf.io/provider-k8s/internal/health"
+	"aerf.io/provider-k8s/internal/metrics"
+	"aerf.io/provider-k8s/internal/objectindex"
+	"aerf.io/provider-k8s/internal/safecmp"
+	"aerf.io/provider-k8s/internal/schemacache"
+)
+
+const errNotObjectSet = "managed resource is not a ObjectSet custom resource"
+
+// SetupObjectSet adds a controller that reconciles ObjectSet managed resources.
+// The registry must not be shared with other controllers, as it enqueues ObjectSets when remote objects change.
+func SetupObjectSet(mgr ctrl.Manager, o controller.Options, registry *cacheregistry.Registry, cfg Config) error {
+	name := managed.ControllerName(objv1alpha1.ObjectSetGroupKind)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(&objectSetConnector{
+			connector: &connector{
+				client:       mgr.GetClient(),
+				usageTracker: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+				logger:       o.Logger,
+				registry:     registry,
+				schemas:      schemacache.New(schemaCacheTTL),
+				recorder:     recorder,
+				config:       cfg,
+			},
+		}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(recorder),
+		managed.WithCreationGracePeriod(3 * time.Second),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(objv1alpha1.ObjectSetGroupVersionKind), opts...)
+
+	objectSetController, err := ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&objv1alpha1.ObjectSet{}).
+		Build(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+	if err != nil {
+		return err
+	}
+	registry.SetRegisterFn(func(inf cache.Informer, parentNameNs types.NamespacedName) error {
+		return objectSetController.Watch(&source.Informer{Informer: inf}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, c client.Object) []reconcile.Request {
+			o.Logger.WithValues("name", "objectset-controller-watch", "objectRef", meta.TypedReferenceTo(c, c.GetObjectKind().GroupVersionKind()), "parentRef", parentNameNs).Debug("enqueuing reconcile request")
+			return []reconcile.Request{
+				{
+					NamespacedName: parentNameNs,
+				},
+			}
+		}))
+	})
+	return nil
+}
+
+type objectSetConnector struct {
+	*connector
+}
+
+func (c *objectSetConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*objv1alpha1.ObjectSet); !ok {
+		return nil, errors.New(errNotObjectSet)
+	}
+
+	ext, err := c.newExternal(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return generic.NewExternalForType[*objv1alpha1.ObjectSet](&objectSetExternal{ext: ext}, errors.New(errNotObjectSet)), nil
+}
+
+// objectSetExternal manages every remote object of an ObjectSet, reusing the apply and drift detection logic of the Object's external client.
+type objectSetExternal struct {
+	ext *external
+
+	// drifted holds the kinds of the drifted remote objects found by Observe, reported once Update corrects them.
+	drifted []schema.GroupVersionKind
+}
+
+func (e *objectSetExternal) Observe(ctx context.Context, cr *objv1alpha1.ObjectSet) (managed.ExternalObservation, error) {
+	log := e.ext.loggerFor(cr)
+	log.Debug("Observing", "reconciledObjectSet", cr)
+
+	ordered, err := orderManifests(cr.Spec.ForProvider.Manifests)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if err := e.stopRemovedCaches(cr, ordered); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	cr.Status.AtProvider.Host = e.ext.remoteRestCfg.Host
+
+	if meta.WasDeleted(cr) {
+		exists := false
+		for _, m := range ordered {
+			if err := e.ext.registry.StopAndRemove(e.ext.remoteRestCfg, m.desired.GroupVersionKind(), client.ObjectKeyFromObject(m.desired)); err != nil {
+				return managed.ExternalObservation{}, err
+			}
+			if e.ext.checkPolicy(m.desired) != nil {
+				// remote objects denied by the policy are left intact, see Delete
+				continue
+			}
+			owner, err := e.owner(ctx, cr, m.desired)
+			if err != nil {
+				return managed.ExternalObservation{}, err
+			}
+			if owner != "" {
+				// so are the remote objects managed by others
+				continue
+			}
+			found, err := e.get(ctx, m.desired.DeepCopy())
+			if err != nil {
+				return managed.ExternalObservation{}, err
+			}
+			exists = exists || found
+		}
+		return managed.ExternalObservation{ResourceExists: exists}, nil
+	}
+
+	observations := make([]objv1alpha1.ObjectSetManifestObservation, 0, len(ordered))
+	diffs := make([]string, 0, len(ordered))
+	anyExists, upToDate := false, true
+	for _, m := range ordered {
+		observation := objv1alpha1.ObjectSetManifestObservation{
+			APIVersion: m.desired.GetAPIVersion(),
+			Kind:       m.desired.GetKind(),
+			Name:       m.desired.GetName(),
+			Namespace:  m.desired.GetNamespace(),
+			Wave:       m.wave,
+		}
+
+		owner, err := e.owner(ctx, cr, m.desired)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if owner != "" {
+			return managed.ExternalObservation{}, errors.Errorf("%s is already managed by %s", manifestRef(m.desired), owner)
+		}
+
+		observed := m.desired.DeepCopy()
+		found, err := e.get(ctx, observed)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if !found {
+			upToDate = false
+			observation.Ready = corev1.ConditionFalse
+			observation.Message = "Remote object does not exist"
+			observations = append(observations, observation)
+			continue
+		}
+		anyExists = true
+
+		if err := e.ext.registry.RegisterCacheFromRestConfig(e.ext.remoteRestCfg, m.desired.GroupVersionKind(), client.ObjectKeyFromObject(m.desired), client.ObjectKeyFromObject(cr)); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+
+		dryRun := m.desired.DeepCopy()
+		if err := e.ext.ApplyDryRun(ctx, dryRun); err != nil {
+			return managed.ExternalObservation{}, errors.Wrapf(err, "failed to dry-run apply %s", manifestRef(m.desired))
+		}
+		comparedObserved, comparedDryRun, err := e.ext.ownedFields(observed, dryRun)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrapf(err, "failed to read the fields owned in %s", manifestRef(m.desired))
+		}
+		if e.ext.hasDrifted(comparedObserved, comparedDryRun) {
+			metrics.DriftDetected.With(metrics.ObjectLabels(m.desired.GroupVersionKind(), e.ext.providerConfig)).Inc()
+			e.drifted = append(e.drifted, m.desired.GroupVersionKind())
+			upToDate = false
+			diffs = append(diffs, fmt.Sprintf("%s:\n%s", manifestRef(m.desired), safecmp.DiffUnstructured(comparedObserved, comparedDryRun)))
+		}
+
+		cond, err := readinessCondition(log, m.readiness, e.ext.config.CELLimits, e.ext.schemas, observed)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrapf(err, "failed to derive readiness of %s", manifestRef(m.desired))
+		}
+		observation.Ready = cond.Status
+		observation.Message = cond.Message
+		observations = append(observations, observation)
+	}
+
+	cr.Status.AtProvider.Manifests = observations
+	cr.SetConditions(aggregateReadiness(observations))
+
+	return managed.ExternalObservation{
+		ResourceExists:   anyExists,
+		ResourceUpToDate: upToDate,
+		Diff:             safecmp.Truncate(strings.Join(diffs, "\n"), e.ext.config.MaxDiffSize),
+	}, nil
+}
+
+// stopRemovedCaches stops the caches of the remote objects observed before, whose manifests were removed since.
+func (e *objectSetExternal) stopRemovedCaches(cr *objv1alpha1.ObjectSet, ordered []orderedManifest) error {
+	current := make(map[string]bool, len(ordered))
+	for _, m := range ordered {
+		current[manifestRef(m.desired)] = true
+	}
+	for _, o := range cr.Status.AtProvider.Manifests {
+		removed := &unstructured.Unstructured{}
+		removed.SetAPIVersion(o.APIVersion)
+		removed.SetKind(o.Kind)
+		removed.SetNamespace(o.Namespace)
+		removed.SetName(o.Name)
+		if current[manifestRef(removed)] {
+			continue
+		}
+		if err := e.ext.registry.StopAndRemove(e.ext.remoteRestCfg, removed.GroupVersionKind(), client.ObjectKeyFromObject(removed)); err != nil {
+			return errors.Wrapf(err, "failed to stop the cache of the removed %s", manifestRef(removed))
+		}
+	}
+	return nil
+}
+
+func (e *objectSetExternal) Create(ctx context.Context, cr *objv1alpha1.ObjectSet) (managed.ExternalCreation, error) {
+	e.ext.loggerFor(cr).Debug("Creating")
+
+	return managed.ExternalCreation{}, e.applyInWaves(ctx, cr)
+}
+
+func (e *objectSetExternal) Update(ctx context.Context, cr *objv1alpha1.ObjectSet) (managed.ExternalUpdate, error) {
+	e.ext.loggerFor(cr).Debug("Updating")
+
+	if err := e.applyInWaves(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	for _, gvk := range e.drifted {
+		metrics.DriftCorrected.With(metrics.ObjectLabels(gvk, e.ext.providerConfig)).Inc()
+	}
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete removes the remote objects wave by wave in reverse order. A wave is deleted only after every remote object
+// from the following waves is gone, the managed reconciler keeps calling Delete as long as Observe reports that some remote object still exists.
+// Likewise the Namespaces and CRDs of a wave are deleted only after the rest of the wave is gone.
+func (e *objectSetExternal) Delete(ctx context.Context, cr *objv1alpha1.ObjectSet) error {
+	log := e.ext.loggerFor(cr)
+	log.Debug("Deleting")
+
+	ordered, err := orderManifests(cr.Spec.ForProvider.Manifests)
+	if err != nil {
+		return err
+	}
+
+	waves := splitIntoWaves(ordered)
+	for i := len(waves) - 1; i >= 0; i-- {
+		remaining := false
+		for j := len(waves[i]) - 1; j >= 0; j-- {
+			if err := e.ext.checkPolicy(waves[i][j].desired); err != nil {
+				// the remote object is left intact, so that the ObjectSet can still be deleted
+				e.ext.recorder.Event(cr, event.Warning(reasonDeletionDeniedByPolicy, errors.Wrap(err, "leaving the remote object in place")))
+				continue
+			}
+			owner, err := e.owner(ctx, cr, waves[i][j].desired)
+			if err != nil {
+				return err
+			}
+			if owner != "" {
+				// the remote object is left to its owner
+				continue
+			}
+			observed := waves[i][j].desired.DeepCopy()
+			found, err := e.get(ctx, observed)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+			// e.g. a Namespace is deleted only after the objects of its wave, which may live in it
+			waiting := remaining && isPrerequisite(observed)
+			remaining = true
+			if waiting || observed.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if err := client.IgnoreNotFound(e.ext.remoteCli.Delete(ctx, observed)); err != nil {
+				return errors.Wrapf(err, "failed to delete %s", manifestRef(observed))
+			}
+		}
+		if remaining {
+			log.Debug("Waiting for the wave to be deleted before deleting the previous ones", "wave", waves[i][0].wave)
+			return nil
+		}
+	}
+	return nil
+}
+
+// applyInWaves applies the manifests in order. The next wave is applied only if every remote object from the previous one is ready,
+// otherwise it's postponed until the ObjectSet is reconciled again, for example due to a change in one of the watched remote objects.
+// So is the rest of a wave until its Namespaces and CRDs are ready.
+func (e *objectSetExternal) applyInWaves(ctx context.Context, cr *objv1alpha1.ObjectSet) error {
+	log := e.ext.loggerFor(cr)
+
+	ordered, err := orderManifests(cr.Spec.ForProvider.Manifests)
+	if err != nil {
+		return err
+	}
+
+	waves := splitIntoWaves(ordered)
+	for i, wave := range waves {
+		ready := true
+		for _, m := range wave {
+			if err := e.ext.checkPolicy(m.desired); err != nil {
+				return err
+			}
+			applied := m.desired.DeepCopy()
+			if err := e.ext.Apply(ctx, applied); err != nil {
+				return errors.Wrapf(err, "failed to apply %s", manifestRef(m.desired))
+			}
+			if isPrerequisite(applied) {
+				if res, err := health.Check(applied); err != nil || !res.Ready {
+					log.Debug("Remote object is not ready yet, postponing the rest of the wave", "object", manifestRef(applied), "wave", wave[0].wave)
+					return nil
+				}
+			}
+			cond, err := readinessCondition(log, m.readiness, e.ext.config.CELLimits, e.ext.schemas, applied)
+			if err != nil || cond.Status != corev1.ConditionTrue {
+				ready = false
+			}
+		}
+		if !ready && i < len(waves)-1 {
+			log.Debug("Wave is not ready yet, postponing the following ones", "wave", wave[0].wave)
+			return nil
+		}
+	}
+	return nil
+}
+
+// owner returns the other Object or ObjectSet managing the remote object of the manifest, see objectindex.Owner.
+func (e *objectSetExternal) owner(ctx context.Context, cr *objv1alpha1.ObjectSet, desired *unstructured.Unstructured) (string, error) {
+	owner, err := objectindex.Owner(ctx, e.ext.localCli, cr, e.ext.remoteRestCfg.Host, desired)
+	return owner, errors.Wrapf(err, "failed to look up the owner of %s", manifestRef(desired))
+}
+
+// get fetches the remote object into obj and reports whether it exists.
+// Objects whose kind is not served by the remote cluster, e.g. because its CRD is not installed yet, are reported as non-existent.
+func (e *objectSetExternal) get(ctx context.Context, obj *unstructured.Unstructured) (bool, error) {
+	err := e.ext.Get(ctx, obj)
+	switch {
+	case err == nil:
+		return true, nil
+	case apierrors.IsNotFound(err), apimeta.IsNoMatchError(err):
+		return false, nil
+	default:
+		return false, errors.Wrapf(err, "failed to get %s", manifestRef(obj))
+	}
+}
+
+func aggregateReadiness(observations []objv1alpha1.ObjectSetManifestObservation) xpv1.Condition {
+	notReady := make([]string, 0, len(observations))
+	for _, o := range observations {
+		if o.Ready == corev1.ConditionTrue {
+			continue
+		}
+		msg := fmt.Sprintf("%s %s", o.Kind, types.NamespacedName{Namespace: o.Namespace, Name: o.Name})
+		if o.Message != "" {
+			msg += ": " + o.Message
+		}
+		notReady = append(notReady, msg)
+	}
+	if len(notReady) == 0 {
+		return xpv1.Available()
+	}
+	return xpv1.Unavailable().WithMessage(fmt.Sprintf("Remote objects are not ready: %s", strings.Join(notReady, "; ")))
+}
+
+func manifestRef(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s %s", obj.GroupVersionKind(), client.ObjectKeyFromObject(obj))
+}
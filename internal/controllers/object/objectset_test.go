@@ -0,0 +1,405 @@
+package object
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+	"aerf.io/provider-k8s/internal/objectindex"
+)
+
+// recorder keeps the recorded events.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(...string) event.Recorder {
+	return r
+}
+
+func configMapManifest(namespace, name string, wave int32) objv1alpha1.ObjectSetManifest {
+	return objv1alpha1.ObjectSetManifest{
+		Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"` + name + `","namespace":"` + namespace + `"}}`)},
+		Wave:     wave,
+	}
+}
+
+func deploymentManifest(name string, wave int32) objv1alpha1.ObjectSetManifest {
+	return objv1alpha1.ObjectSetManifest{
+		Manifest:  runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"` + name + `","namespace":"default"}}`)},
+		Wave:      wave,
+		Readiness: objv1alpha1.Readiness{Policy: objv1alpha1.ReadinessPolicyAuto},
+	}
+}
+
+func namespaceManifest(name string, wave int32) objv1alpha1.ObjectSetManifest {
+	return objv1alpha1.ObjectSetManifest{
+		Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"` + name + `"}}`)},
+		Wave:     wave,
+	}
+}
+
+func crdManifest(name string, wave int32) objv1alpha1.ObjectSetManifest {
+	return objv1alpha1.ObjectSetManifest{
+		Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"apiextensions.k8s.io/v1","kind":"CustomResourceDefinition","metadata":{"name":"` + name + `"}}`)},
+		Wave:     wave,
+	}
+}
+
+func objectSetOf(manifests ...objv1alpha1.ObjectSetManifest) *objv1alpha1.ObjectSet {
+	return &objv1alpha1.ObjectSet{Spec: objv1alpha1.ObjectSetSpec{ForProvider: objv1alpha1.ObjectSetParameters{Manifests: manifests}}}
+}
+
+func remoteScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, extv1.AddToScheme(scheme))
+	return scheme
+}
+
+// newApplyClient returns a fake client of the remote cluster serving server-side applies, which the fake client
+// doesn't support, as creates or updates of the existing object overlaid with the top-level fields of the manifest.
+// Dry-run applies return the overlaid object without persisting it. The names of the applied objects are recorded.
+func newApplyClient(scheme *runtime.Scheme, applied *[]string, objs ...client.Object) client.WithWatch {
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok || patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			dryRun := len((&client.PatchOptions{}).ApplyOptions(opts).DryRun) > 0
+			if !dryRun {
+				*applied = append(*applied, u.GetName())
+			}
+
+			existing := &unstructured.Unstructured{}
+			existing.SetGroupVersionKind(u.GroupVersionKind())
+			err := c.Get(ctx, client.ObjectKeyFromObject(u), existing)
+			switch {
+			case apierrors.IsNotFound(err) && dryRun:
+				return nil
+			case apierrors.IsNotFound(err):
+				return c.Create(ctx, u)
+			case err != nil:
+				return err
+			}
+			// the top-level fields of the manifest replace the existing ones, the rest of the existing object is kept
+			for k, v := range u.Object {
+				if k != "metadata" && k != "status" {
+					existing.Object[k] = v
+				}
+			}
+			existing.SetLabels(u.GetLabels())
+			existing.SetAnnotations(u.GetAnnotations())
+			u.Object = existing.Object
+			if dryRun {
+				return nil
+			}
+			return c.Update(ctx, u)
+		},
+	}).Build()
+}
+
+// registry records the caches registered and stopped. It never serves reads, so they go to the remote client.
+type registry struct {
+	registered, stopped []string
+}
+
+func (r *registry) Get(context.Context, *rest.Config, *unstructured.Unstructured) (bool, error) {
+	return false, nil
+}
+
+func (r *registry) RegisterCacheFromRestConfig(_ *rest.Config, gvk schema.GroupVersionKind, nameNs, _ types.NamespacedName) error {
+	r.registered = append(r.registered, gvk.Kind+" "+nameNs.String())
+	return nil
+}
+
+func (r *registry) StopAndRemove(_ *rest.Config, gvk schema.GroupVersionKind, nameNs types.NamespacedName) error {
+	r.stopped = append(r.stopped, gvk.Kind+" "+nameNs.String())
+	return nil
+}
+
+// newLocalClient returns a fake client of the local cluster with the given Objects and ObjectSets, serving the
+// objectindex indexes.
+func newLocalClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, objv1alpha1.SchemeBuilder.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithIndex(&objv1alpha1.Object{}, objectindex.Field, objectindex.IndexObject).
+		WithIndex(&objv1alpha1.ObjectSet{}, objectindex.Field, objectindex.IndexObjectSet).
+		Build()
+}
+
+// newObjectSetExternal returns the external client of ObjectSets for the remote cluster of remoteCli, reading the
+// remote objects from it directly, as no cache is registered. No other Object or ObjectSet exists.
+func newObjectSetExternal(t *testing.T, remoteCli client.Client, pcPolicy apisv1alpha1.Policy, rec event.Recorder) *objectSetExternal {
+	t.Helper()
+	return &objectSetExternal{ext: &external{
+		localCli:      newLocalClient(t),
+		remoteCli:     remoteCli,
+		log:           logging.NewNopLogger(),
+		registry:      &registry{},
+		recorder:      rec,
+		remoteRestCfg: &rest.Config{Host: "https://remote.example.com"},
+		pcPolicy:      pcPolicy,
+	}}
+}
+
+func TestObjectSetDeleteDeniedByPolicy(t *testing.T) {
+	remoteCli := fake.NewClientBuilder().WithScheme(remoteScheme(t)).WithObjects(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "allowed", Namespace: "default"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "denied", Namespace: "kube-system"}},
+	).Build()
+	rec := &recorder{}
+	e := newObjectSetExternal(t, remoteCli, apisv1alpha1.Policy{Deny: []apisv1alpha1.PolicyRule{{Namespaces: []string{"kube-system"}}}}, rec)
+
+	cr := objectSetOf(configMapManifest("default", "allowed", 0), configMapManifest("kube-system", "denied", 1))
+	require.NoError(t, e.Delete(context.Background(), cr))
+
+	require.True(t, apierrors.IsNotFound(remoteCli.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "allowed"}, &corev1.ConfigMap{})))
+	require.NoError(t, remoteCli.Get(context.Background(), client.ObjectKey{Namespace: "kube-system", Name: "denied"}, &corev1.ConfigMap{}))
+	require.Len(t, rec.events, 1)
+	require.Equal(t, reasonDeletionDeniedByPolicy, rec.events[0].Reason)
+}
+
+func TestObjectSetApplyWaitsForPrerequisites(t *testing.T) {
+	activeNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ns"},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+	}
+	establishedCRD := &extv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Status: extv1.CustomResourceDefinitionStatus{Conditions: []extv1.CustomResourceDefinitionCondition{
+			{Type: extv1.Established, Status: extv1.ConditionTrue},
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		existing  []client.Object
+		manifests []objv1alpha1.ObjectSetManifest
+		want      []string
+	}{
+		{
+			name:      "new Namespace",
+			manifests: []objv1alpha1.ObjectSetManifest{configMapManifest("ns", "cm", 0), namespaceManifest("ns", 0)},
+			want:      []string{"ns"},
+		},
+		{
+			name:      "active Namespace",
+			existing:  []client.Object{activeNamespace},
+			manifests: []objv1alpha1.ObjectSetManifest{configMapManifest("ns", "cm", 0), namespaceManifest("ns", 0)},
+			want:      []string{"ns", "cm"},
+		},
+		{
+			name:      "new CRD",
+			manifests: []objv1alpha1.ObjectSetManifest{configMapManifest("default", "cm", 0), crdManifest("widgets.example.com", 0), configMapManifest("default", "next", 1)},
+			want:      []string{"widgets.example.com"},
+		},
+		{
+			name:      "established CRD",
+			existing:  []client.Object{establishedCRD},
+			manifests: []objv1alpha1.ObjectSetManifest{configMapManifest("default", "cm", 0), crdManifest("widgets.example.com", 0), configMapManifest("default", "next", 1)},
+			want:      []string{"widgets.example.com", "cm", "next"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var applied []string
+			e := newObjectSetExternal(t, newApplyClient(remoteScheme(t), &applied, tt.existing...), apisv1alpha1.Policy{}, &recorder{})
+
+			require.NoError(t, e.applyInWaves(context.Background(), objectSetOf(tt.manifests...)))
+			require.Equal(t, tt.want, applied)
+		})
+	}
+}
+
+func TestObjectSetDeleteNamespaceLast(t *testing.T) {
+	remoteCli := newApplyClient(remoteScheme(t), nil,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "ns"}},
+	)
+	e := newObjectSetExternal(t, remoteCli, apisv1alpha1.Policy{}, &recorder{})
+	cr := objectSetOf(namespaceManifest("ns", 0), configMapManifest("ns", "cm", 0))
+
+	require.NoError(t, e.Delete(context.Background(), cr))
+	require.True(t, apierrors.IsNotFound(remoteCli.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "cm"}, &corev1.ConfigMap{})))
+	require.NoError(t, remoteCli.Get(context.Background(), client.ObjectKey{Name: "ns"}, &corev1.Namespace{}))
+
+	require.NoError(t, e.Delete(context.Background(), cr))
+	require.True(t, apierrors.IsNotFound(remoteCli.Get(context.Background(), client.ObjectKey{Name: "ns"}, &corev1.Namespace{})))
+}
+
+func TestObjectSetObserveStopsRemovedCaches(t *testing.T) {
+	remoteCli := newApplyClient(remoteScheme(t), nil, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "kept", Namespace: "default"}})
+	e := newObjectSetExternal(t, remoteCli, apisv1alpha1.Policy{}, &recorder{})
+	cr := objectSetOf(configMapManifest("default", "kept", 0))
+	cr.Status.AtProvider.Manifests = []objv1alpha1.ObjectSetManifestObservation{
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "kept"},
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "removed"},
+	}
+
+	_, err := e.Observe(context.Background(), cr)
+	require.NoError(t, err)
+	reg := e.ext.registry.(*registry) //nolint:forcetypeassert // set by newObjectSetExternal
+	require.Equal(t, []string{"ConfigMap default/removed"}, reg.stopped)
+	require.Equal(t, []string{"ConfigMap default/kept"}, reg.registered)
+	require.Len(t, cr.Status.AtProvider.Manifests, 1)
+}
+
+func TestObjectSetObserve(t *testing.T) {
+	tests := []struct {
+		name         string
+		existing     []client.Object
+		manifests    []objv1alpha1.ObjectSetManifest
+		wantExists   bool
+		wantUpToDate bool
+		wantDiff     bool
+		wantReady    xpv1.Condition
+	}{
+		{
+			name:      "no remote objects",
+			manifests: []objv1alpha1.ObjectSetManifest{configMapManifest("default", "cm", 0)},
+			wantReady: xpv1.Unavailable().WithMessage("Remote objects are not ready: ConfigMap default/cm: Remote object does not exist"),
+		},
+		{
+			name:         "ready remote objects",
+			existing:     []client.Object{&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}},
+			manifests:    []objv1alpha1.ObjectSetManifest{configMapManifest("default", "cm", 0)},
+			wantExists:   true,
+			wantUpToDate: true,
+			wantReady:    xpv1.Available(),
+		},
+		{
+			name: "one remote object not ready",
+			existing: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}},
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+			},
+			manifests:    []objv1alpha1.ObjectSetManifest{configMapManifest("default", "cm", 0), deploymentManifest("web", 1)},
+			wantExists:   true,
+			wantUpToDate: true,
+			wantReady:    xpv1.Unavailable().WithMessage("Remote objects are not ready: Deployment default/web: Updated replicas: 0/1"),
+		},
+		{
+			name: "drifted remote object",
+			existing: []client.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"},
+				Data:       map[string]string{"key": "changed"},
+			}},
+			manifests: []objv1alpha1.ObjectSetManifest{{
+				Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"default"},"data":{"key":"value"}}`)},
+			}},
+			wantExists: true,
+			wantDiff:   true,
+			wantReady:  xpv1.Available(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newObjectSetExternal(t, newApplyClient(remoteScheme(t), nil, tt.existing...), apisv1alpha1.Policy{}, &recorder{})
+			cr := objectSetOf(tt.manifests...)
+
+			obs, err := e.Observe(context.Background(), cr)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantExists, obs.ResourceExists)
+			require.Equal(t, tt.wantUpToDate, obs.ResourceUpToDate)
+			require.Equal(t, tt.wantDiff, obs.Diff != "")
+			require.Equal(t, tt.wantDiff, len(e.drifted) > 0)
+			require.True(t, cr.GetCondition(xpv1.TypeReady).Equal(tt.wantReady), cr.GetCondition(xpv1.TypeReady))
+			require.Len(t, cr.Status.AtProvider.Manifests, len(tt.manifests))
+		})
+	}
+}
+
+func TestObjectSetApplyStopsAtNotReadyWave(t *testing.T) {
+	var applied []string
+	e := newObjectSetExternal(t, newApplyClient(remoteScheme(t), &applied), apisv1alpha1.Policy{}, &recorder{})
+	cr := objectSetOf(configMapManifest("default", "first", 0), deploymentManifest("web", 1), configMapManifest("default", "last", 2))
+
+	require.NoError(t, e.applyInWaves(context.Background(), cr))
+	require.Equal(t, []string{"first", "web"}, applied)
+}
+
+func TestObjectSetDeleteInReverseWaves(t *testing.T) {
+	remoteCli := newApplyClient(remoteScheme(t), nil,
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "default"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "default", Finalizers: []string{"example.com/cleanup"}}},
+	)
+	e := newObjectSetExternal(t, remoteCli, apisv1alpha1.Policy{}, &recorder{})
+	cr := objectSetOf(configMapManifest("default", "first", 0), configMapManifest("default", "second", 1))
+	ctx := context.Background()
+
+	// the later wave is deleted first, but its remote object is kept by the finalizer
+	require.NoError(t, e.Delete(ctx, cr))
+	second := &corev1.ConfigMap{}
+	require.NoError(t, remoteCli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "second"}, second))
+	require.NotNil(t, second.GetDeletionTimestamp())
+	require.NoError(t, remoteCli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "first"}, &corev1.ConfigMap{}))
+
+	// the earlier wave waits for the remaining remote object
+	require.NoError(t, e.Delete(ctx, cr))
+	require.NoError(t, remoteCli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "first"}, &corev1.ConfigMap{}))
+
+	second.SetFinalizers(nil)
+	require.NoError(t, remoteCli.Update(ctx, second))
+	require.NoError(t, e.Delete(ctx, cr))
+	require.True(t, apierrors.IsNotFound(remoteCli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "first"}, &corev1.ConfigMap{})))
+}
+
+func TestObjectSetRemoteObjectManagedByObject(t *testing.T) {
+	const host = "https://remote.example.com"
+	owner := &objv1alpha1.Object{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour))},
+		Spec: objv1alpha1.ObjectSpec{ForProvider: objv1alpha1.ObjectParameters{
+			Manifest: configMapManifest("default", "shared", 0).Manifest,
+		}},
+	}
+	owner.Status.AtProvider.Host = host
+	remoteCli := newApplyClient(remoteScheme(t), nil,
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "own", Namespace: "default"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default"}},
+	)
+	e := newObjectSetExternal(t, remoteCli, apisv1alpha1.Policy{}, &recorder{})
+	e.ext.localCli = newLocalClient(t, owner)
+	cr := objectSetOf(configMapManifest("default", "own", 0), configMapManifest("default", "shared", 0))
+	cr.SetName("set")
+	cr.SetCreationTimestamp(metav1.Now())
+	ctx := context.Background()
+
+	_, err := e.Observe(ctx, cr)
+	require.ErrorContains(t, err, `is already managed by Object "owner"`)
+	require.Equal(t, host, cr.Status.AtProvider.Host)
+
+	// only the own remote objects are deleted
+	require.NoError(t, e.Delete(ctx, cr))
+	require.True(t, apierrors.IsNotFound(remoteCli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "own"}, &corev1.ConfigMap{})))
+	require.NoError(t, remoteCli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "shared"}, &corev1.ConfigMap{}))
+}
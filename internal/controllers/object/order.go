@@ -0,0 +1,119 @@
+package object
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+)
+
+// kindOrder is the order in which objects of a given kind are applied within a single wave, based on Helm's install order.
+// Namespaces and CRDs go first, so that the objects living in them or using them can be applied once they're ready,
+// see prerequisiteKinds.
+// Kinds not present on this list are applied last.
+var kindOrder = []string{
+	"Namespace",
+	"CustomResourceDefinition",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodSecurityPolicy",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"APIService",
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+}
+
+// prerequisiteKinds are the kinds of the objects that the following objects of the same wave may live in or be
+// instances of. The rest of the wave is applied only once they're ready, and they're deleted only once the rest of
+// the wave is gone.
+var prerequisiteKinds = map[schema.GroupKind]bool{
+	{Group: "", Kind: "Namespace"}:                                    true,
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: true,
+}
+
+func isPrerequisite(obj *unstructured.Unstructured) bool {
+	return prerequisiteKinds[obj.GroupVersionKind().GroupKind()]
+}
+
+var kindRank = func() map[string]int {
+	ranks := make(map[string]int, len(kindOrder))
+	for i, kind := range kindOrder {
+		ranks[kind] = i
+	}
+	return ranks
+}()
+
+func rankOfKind(kind string) int {
+	if rank, ok := kindRank[kind]; ok {
+		return rank
+	}
+	return len(kindOrder)
+}
+
+type orderedManifest struct {
+	desired   *unstructured.Unstructured
+	wave      int32
+	readiness objv1alpha1.Readiness
+}
+
+// orderManifests decodes the manifests and sorts them by wave first and by kind second.
+// Manifests with the same wave and kind keep the order from the spec.
+func orderManifests(manifests []objv1alpha1.ObjectSetManifest) ([]orderedManifest, error) {
+	ordered := make([]orderedManifest, 0, len(manifests))
+	for i := range manifests {
+		desired, err := manifests[i].GetDesired()
+		if err != nil {
+			return nil, err
+		}
+		ordered = append(ordered, orderedManifest{
+			desired:   desired,
+			wave:      manifests[i].Wave,
+			readiness: manifests[i].Readiness,
+		})
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].wave != ordered[j].wave {
+			return ordered[i].wave < ordered[j].wave
+		}
+		return rankOfKind(ordered[i].desired.GetKind()) < rankOfKind(ordered[j].desired.GetKind())
+	})
+	return ordered, nil
+}
+
+// splitIntoWaves groups already ordered manifests by their wave.
+func splitIntoWaves(ordered []orderedManifest) [][]orderedManifest {
+	var waves [][]orderedManifest
+	for i := range ordered {
+		if i == 0 || ordered[i].wave != ordered[i-1].wave {
+			waves = append(waves, nil)
+		}
+		waves[len(waves)-1] = append(waves[len(waves)-1], ordered[i])
+	}
+	return waves
+}
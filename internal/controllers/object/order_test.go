@@ -0,0 +1,79 @@
+package object
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+)
+
+func manifest(kind, name string, wave int32) objv1alpha1.ObjectSetManifest {
+	return objv1alpha1.ObjectSetManifest{
+		Manifest: runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":%q,"metadata":{"name":%q}}`, kind, name))},
+		Wave:     wave,
+	}
+}
+
+func TestOrderManifests(t *testing.T) {
+	tests := []struct {
+		name      string
+		manifests []objv1alpha1.ObjectSetManifest
+		want      [][]string
+	}{
+		{
+			name: "namespaces and CRDs go first",
+			manifests: []objv1alpha1.ObjectSetManifest{
+				manifest("Deployment", "deploy", 0),
+				manifest("MyCustomResource", "cr", 0),
+				manifest("CustomResourceDefinition", "crd", 0),
+				manifest("ServiceAccount", "sa", 0),
+				manifest("Namespace", "ns", 0),
+			},
+			want: [][]string{{"ns", "crd", "sa", "deploy", "cr"}},
+		},
+		{
+			name: "same kind keeps the order from spec",
+			manifests: []objv1alpha1.ObjectSetManifest{
+				manifest("ConfigMap", "b", 0),
+				manifest("ConfigMap", "a", 0),
+				manifest("Namespace", "ns", 0),
+				manifest("ConfigMap", "c", 0),
+			},
+			want: [][]string{{"ns", "b", "a", "c"}},
+		},
+		{
+			name: "waves take precedence over kinds",
+			manifests: []objv1alpha1.ObjectSetManifest{
+				manifest("Namespace", "late-ns", 2),
+				manifest("Deployment", "deploy", -1),
+				manifest("ConfigMap", "cm", 0),
+				manifest("Namespace", "ns", 0),
+			},
+			want: [][]string{{"deploy"}, {"ns", "cm"}, {"late-ns"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered, err := orderManifests(tt.manifests)
+			require.NoError(t, err)
+
+			got := make([][]string, 0, len(tt.want))
+			for _, wave := range splitIntoWaves(ordered) {
+				names := make([]string, 0, len(wave))
+				for _, m := range wave {
+					names = append(names, m.desired.GetName())
+				}
+				got = append(got, names)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOrderManifestsInvalidManifest(t *testing.T) {
+	_, err := orderManifests([]objv1alpha1.ObjectSetManifest{{Manifest: runtime.RawExtension{Raw: []byte(`{`)}}})
+	require.Error(t, err)
+}
@@ -0,0 +1,35 @@
+package object
+
+import (
+	"encoding/json"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"aerf.io/provider-k8s/internal/celcheck"
+)
+
+// projectStatus evaluates the status projections against the observed remote object.
+// Projections that fail to evaluate are skipped, as it usually means the remote object has not reported the projected fields yet.
+func projectStatus(log logging.Logger, projections map[string]string, celLimits celcheck.Limits, observed *unstructured.Unstructured) (map[string]extv1.JSON, error) {
+	if len(projections) == 0 {
+		return nil, nil
+	}
+
+	fields := make(map[string]extv1.JSON, len(projections))
+	for name, exp := range projections {
+		val, err := celcheck.EvalValue(exp, observed.UnstructuredContent(), celLimits)
+		if err != nil {
+			log.Debug("Skipping status projection that failed to evaluate", "projection", name, "error", err)
+			continue
+		}
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot marshal the result of status projection %q", name)
+		}
+		fields[name] = extv1.JSON{Raw: raw}
+	}
+	return fields, nil
+}
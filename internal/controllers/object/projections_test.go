@@ -0,0 +1,66 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/stretchr/testify/require"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"aerf.io/provider-k8s/internal/celcheck"
+)
+
+func TestProjectStatus(t *testing.T) {
+	observed := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "deploy", "namespace": "default"},
+		"status": map[string]any{
+			"readyReplicas": int64(2),
+			"conditions": []any{
+				map[string]any{"type": "Available", "status": "True"},
+			},
+		},
+	}}
+
+	tests := []struct {
+		name        string
+		projections map[string]string
+		want        map[string]extv1.JSON
+	}{
+		{
+			name: "no projections",
+		},
+		{
+			name: "values keep their types",
+			projections: map[string]string{
+				"replicas":  "status.readyReplicas",
+				"available": "status.conditions.exists(c, c.type == 'Available' && c.status == 'True')",
+				"name":      "metadata.name",
+			},
+			want: map[string]extv1.JSON{
+				"replicas":  {Raw: []byte(`2`)},
+				"available": {Raw: []byte(`true`)},
+				"name":      {Raw: []byte(`"deploy"`)},
+			},
+		},
+		{
+			name: "failing projections are skipped",
+			projections: map[string]string{
+				"missing": "status.updatedReplicas",
+				"name":    "metadata.name",
+			},
+			want: map[string]extv1.JSON{
+				"name": {Raw: []byte(`"deploy"`)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := projectStatus(logging.NewNopLogger(), tt.projections, celcheck.Limits{}, observed)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
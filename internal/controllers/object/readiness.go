@@ -0,0 +1,175 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apiserver/pkg/cel/openapi/resolver"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	"aerf.io/provider-k8s/internal/celcheck"
+	"aerf.io/provider-k8s/internal/health"
+	"aerf.io/provider-k8s/internal/metrics"
+)
+
+const reasonReadinessExpressionTooExpensive xpv1.ConditionReason = "ReadinessExpressionTooExpensive"
+
+// readinessCondition computes the Ready condition of the observed remote object according to the readiness settings.
+// The CEL expressions are evaluated within celLimits, unless overridden by the readiness settings, with `self` typed
+// by the observed object's schema resolved by schemas, if it's not nil and the schema is found.
+// The returned condition has an empty type if it should not be set, which happens only alongside a non-nil error.
+func readinessCondition(log logging.Logger, readiness objv1alpha1.Readiness, celLimits celcheck.Limits, schemas resolver.SchemaResolver, observed *unstructured.Unstructured) (_ xpv1.Condition, err error) {
+	defer func() {
+		if err != nil {
+			gvk := observed.GroupVersionKind()
+			metrics.ReadinessErrors.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind, string(readiness.Policy)).Inc()
+		}
+	}()
+
+	switch readiness.Policy {
+	case objv1alpha1.ReadinessPolicyDeriveFromObject:
+		conditioned := xpv1.ConditionedStatus{}
+		err := fieldpath.Pave(observed.Object).GetValueInto("status", &conditioned)
+		if err != nil {
+			log.Debug("Got error while getting conditions from observed object, setting it as Unavailable", "error", err, "observed", observed)
+			return xpv1.Unavailable().WithMessage("Got error while getting conditions from observed object"), errors.Wrap(err, "failed to get conditions from observed object")
+		}
+		if status := conditioned.GetCondition(xpv1.TypeReady).Status; status != corev1.ConditionTrue {
+			log.Debug("Observed object is not ready, setting it as Unavailable", "status", status, "observed", observed)
+			return xpv1.Unavailable().WithMessage(fmt.Sprintf("Observed object's condition with type %q is %q but should be %q", xpv1.TypeReady, status, corev1.ConditionTrue)), nil
+		}
+		if !isObservedGenerationCurrent(observed) {
+			log.Debug("Observed object is not ready, setting it as Unavailable", "observed", observed)
+			return xpv1.Unavailable().WithMessage(msgObservedGenerationOutdated), nil
+		}
+
+		return xpv1.Available(), nil
+	case objv1alpha1.ReadinessPolicySuccessfulCreate, "":
+		return xpv1.Available(), nil
+	case objv1alpha1.ReadinessPolicyUseCELExpression:
+		if readiness.CELCostLimit != nil {
+			celLimits.Cost = uint64(*readiness.CELCostLimit)
+		}
+		var schema *spec.Schema
+		if schemas != nil {
+			var resolveErr error
+			if schema, resolveErr = schemas.ResolveSchema(observed.GroupVersionKind()); resolveErr != nil {
+				log.Debug("Cannot resolve the schema of observed object, self is untyped in the CEL expression", "error", resolveErr)
+			}
+		}
+		res, err := celcheck.EvalReadiness(readiness.CELExpression, observed.UnstructuredContent(), schema, celLimits)
+		if errors.Is(err, celcheck.ErrLimitExceeded) {
+			cond := xpv1.Unavailable().WithMessage(err.Error())
+			cond.Reason = reasonReadinessExpressionTooExpensive
+			return cond, nil
+		}
+		if err != nil {
+			return xpv1.Condition{}, errors.Wrap(err, "failed to run CEL expression on observed object")
+		}
+		cond := xpv1.Unavailable()
+		if res.Ready {
+			cond = xpv1.Available()
+		}
+		if res.Reason != "" {
+			cond.Reason = xpv1.ConditionReason(res.Reason)
+		}
+		return cond.WithMessage(res.Message), nil
+	case objv1alpha1.ReadinessPolicyMatchConditions:
+		return matchConditions(readiness, observed)
+	case objv1alpha1.ReadinessPolicyAuto:
+		res, err := health.Check(observed)
+		if err != nil {
+			return xpv1.Condition{}, errors.Wrap(err, "failed to check the health of observed object")
+		}
+		if res.Ready {
+			return xpv1.Available(), nil
+		}
+		return xpv1.Unavailable().WithMessage(res.Message), nil
+	default:
+		// should never happen
+		return xpv1.Condition{}, errors.Errorf("unknown readiness policy %q", readiness.Policy)
+	}
+}
+
+const msgObservedGenerationOutdated = "Observed object's status.observedGeneration is not equal to metadata.generation"
+
+// isObservedGenerationCurrent reports whether the observed object's status describes its latest generation.
+// Objects that don't report status.observedGeneration are considered current.
+func isObservedGenerationCurrent(observed *unstructured.Unstructured) bool {
+	obsrvdGenStatus := objv1alpha1.StatusWithObservedGeneration{}
+	if err := fieldpath.Pave(observed.Object).GetValueInto("status", &obsrvdGenStatus); err != nil {
+		return true
+	}
+	return observed.GetGeneration() == obsrvdGenStatus.ObservedGeneration
+}
+
+// remoteCondition is a condition reported by the observed object, following the Kubernetes API conventions.
+type remoteCondition struct {
+	Type    string                 `json:"type"`
+	Status  corev1.ConditionStatus `json:"status"`
+	Reason  string                 `json:"reason,omitempty"`
+	Message string                 `json:"message,omitempty"`
+}
+
+func (c remoteCondition) details() string {
+	parts := make([]string, 0, 2)
+	for _, part := range []string{c.Reason, c.Message} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ": ")
+}
+
+// matchConditions marks the observed object as ready if its conditions match the listed ones. The message of an
+// unavailable object lists the conditions that didn't match.
+func matchConditions(readiness objv1alpha1.Readiness, observed *unstructured.Unstructured) (xpv1.Condition, error) {
+	var conditions []remoteCondition
+	if err := fieldpath.Pave(observed.Object).GetValueInto("status.conditions", &conditions); err != nil && !fieldpath.IsNotFound(err) {
+		return xpv1.Unavailable().WithMessage("Got error while getting conditions from observed object"), errors.Wrap(err, "failed to get conditions from observed object")
+	}
+	reported := make(map[string]remoteCondition, len(conditions))
+	for _, c := range conditions {
+		reported[c.Type] = c
+	}
+
+	var failures []string
+	for _, want := range readiness.Conditions {
+		wantStatus := want.Status
+		if wantStatus == "" {
+			wantStatus = corev1.ConditionTrue
+		}
+		got, ok := reported[want.Type]
+		switch {
+		case !ok:
+			failures = append(failures, fmt.Sprintf("condition %q is not reported", want.Type))
+		case got.Status != wantStatus:
+			failure := fmt.Sprintf("condition %q is %q but should be %q", want.Type, got.Status, wantStatus)
+			if details := got.details(); details != "" {
+				failure += fmt.Sprintf(" (%s)", details)
+			}
+			failures = append(failures, failure)
+		}
+	}
+
+	matched := len(readiness.Conditions) - len(failures)
+	ready := matched == len(readiness.Conditions)
+	if readiness.ConditionsMatch == objv1alpha1.ConditionsMatchAny {
+		ready = matched > 0
+	}
+	if !ready {
+		return xpv1.Unavailable().WithMessage("Observed object's conditions don't match: " + strings.Join(failures, "; ")), nil
+	}
+
+	if readiness.CheckObservedGeneration && !isObservedGenerationCurrent(observed) {
+		return xpv1.Unavailable().WithMessage(msgObservedGenerationOutdated), nil
+	}
+	return xpv1.Available(), nil
+}
@@ -0,0 +1,152 @@
+package object
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	"aerf.io/provider-k8s/internal/celcheck"
+)
+
+func TestMatchConditions(t *testing.T) {
+	deploy := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "deploy", "generation": int64(2)},
+		"status": map[string]any{
+			"observedGeneration": int64(1),
+			"conditions": []any{
+				map[string]any{"type": "Available", "status": "True"},
+				map[string]any{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded", "message": "ReplicaSet has timed out progressing."},
+			},
+		},
+	}}
+
+	tests := []struct {
+		name        string
+		readiness   objv1alpha1.Readiness
+		wantStatus  corev1.ConditionStatus
+		wantMessage string
+	}{
+		{
+			name:       "all conditions match",
+			readiness:  objv1alpha1.Readiness{Conditions: []objv1alpha1.ReadinessCondition{{Type: "Available"}, {Type: "Progressing", Status: corev1.ConditionFalse}}},
+			wantStatus: corev1.ConditionTrue,
+		},
+		{
+			name:        "not all conditions match",
+			readiness:   objv1alpha1.Readiness{Conditions: []objv1alpha1.ReadinessCondition{{Type: "Available"}, {Type: "Progressing"}, {Type: "Complete"}}},
+			wantStatus:  corev1.ConditionFalse,
+			wantMessage: `Observed object's conditions don't match: condition "Progressing" is "False" but should be "True" (ProgressDeadlineExceeded: ReplicaSet has timed out progressing.); condition "Complete" is not reported`,
+		},
+		{
+			name: "any condition matches",
+			readiness: objv1alpha1.Readiness{
+				Conditions:      []objv1alpha1.ReadinessCondition{{Type: "Complete"}, {Type: "Available"}},
+				ConditionsMatch: objv1alpha1.ConditionsMatchAny,
+			},
+			wantStatus: corev1.ConditionTrue,
+		},
+		{
+			name: "no condition matches",
+			readiness: objv1alpha1.Readiness{
+				Conditions:      []objv1alpha1.ReadinessCondition{{Type: "Complete"}},
+				ConditionsMatch: objv1alpha1.ConditionsMatchAny,
+			},
+			wantStatus:  corev1.ConditionFalse,
+			wantMessage: `Observed object's conditions don't match: condition "Complete" is not reported`,
+		},
+		{
+			name: "observed generation is outdated",
+			readiness: objv1alpha1.Readiness{
+				Conditions:              []objv1alpha1.ReadinessCondition{{Type: "Available"}},
+				CheckObservedGeneration: true,
+			},
+			wantStatus:  corev1.ConditionFalse,
+			wantMessage: msgObservedGenerationOutdated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.readiness.Policy = objv1alpha1.ReadinessPolicyMatchConditions
+			cond, err := readinessCondition(logging.NewNopLogger(), tt.readiness, celcheck.Limits{}, nil, deploy)
+			require.NoError(t, err)
+			require.Equal(t, xpv1.TypeReady, cond.Type)
+			require.Equal(t, tt.wantStatus, cond.Status)
+			require.Equal(t, tt.wantMessage, cond.Message)
+		})
+	}
+}
+
+func TestReadinessExpressionLimits(t *testing.T) {
+	cm := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"data":       map[string]any{"a": "1", "b": "2", "c": "3"},
+	}}
+	quadratic := `data.all(k, data.all(l, data[k] + data[l] != ""))`
+
+	tests := []struct {
+		name       string
+		readiness  objv1alpha1.Readiness
+		limits     celcheck.Limits
+		wantStatus corev1.ConditionStatus
+		wantReason xpv1.ConditionReason
+	}{
+		{
+			name:       "within the default limit",
+			readiness:  objv1alpha1.Readiness{CELExpression: quadratic},
+			limits:     celcheck.Limits{Cost: 1000},
+			wantStatus: corev1.ConditionTrue,
+			wantReason: xpv1.ReasonAvailable,
+		},
+		{
+			name:       "exceeds the default limit",
+			readiness:  objv1alpha1.Readiness{CELExpression: quadratic},
+			limits:     celcheck.Limits{Cost: 10},
+			wantStatus: corev1.ConditionFalse,
+			wantReason: reasonReadinessExpressionTooExpensive,
+		},
+		{
+			name:       "exceeds the overridden limit",
+			readiness:  objv1alpha1.Readiness{CELExpression: quadratic, CELCostLimit: ptr.To[int64](10)},
+			limits:     celcheck.Limits{Cost: 1000},
+			wantStatus: corev1.ConditionFalse,
+			wantReason: reasonReadinessExpressionTooExpensive,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.readiness.Policy = objv1alpha1.ReadinessPolicyUseCELExpression
+			cond, err := readinessCondition(logging.NewNopLogger(), tt.readiness, tt.limits, nil, cm)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatus, cond.Status)
+			require.Equal(t, tt.wantReason, cond.Reason)
+		})
+	}
+}
+
+func TestReadinessExpressionResult(t *testing.T) {
+	svc := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"spec":       map[string]any{"type": "LoadBalancer"},
+		"status":     map[string]any{},
+	}}
+	readiness := objv1alpha1.Readiness{
+		Policy:        objv1alpha1.ReadinessPolicyUseCELExpression,
+		CELExpression: `{"ready": has(status.loadBalancer), "reason": "Provisioning", "message": "waiting for the load balancer"}`,
+	}
+
+	cond, err := readinessCondition(logging.NewNopLogger(), readiness, celcheck.Limits{}, nil, svc)
+	require.NoError(t, err)
+	require.Equal(t, corev1.ConditionFalse, cond.Status)
+	require.Equal(t, xpv1.ConditionReason("Provisioning"), cond.Reason)
+	require.Equal(t, "waiting for the load balancer", cond.Message)
+}
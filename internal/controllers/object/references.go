@@ -0,0 +1,79 @@
+package object
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+)
+
+// desiredFor returns the Object's manifest with all references resolved and injected.
+func (e *external) desiredFor(ctx context.Context, cr *objv1alpha1.Object) (*unstructured.Unstructured, error) {
+	desired, err := cr.GetDesired()
+	if err != nil {
+		return nil, err
+	}
+
+	paved := fieldpath.Pave(desired.Object)
+	for i, ref := range cr.Spec.References {
+		val, err := e.resolveReference(ctx, ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot resolve reference %d", i)
+		}
+		if err := paved.SetValue(ref.ToFieldPath, val); err != nil {
+			return nil, errors.Wrapf(err, "cannot set %q field of the manifest from reference %d", ref.ToFieldPath, i)
+		}
+	}
+	desired.SetUnstructuredContent(paved.UnstructuredContent())
+
+	return desired, nil
+}
+
+func (e *external) resolveReference(ctx context.Context, ref objv1alpha1.Reference) (any, error) {
+	switch {
+	case ref.Object != nil:
+		source := &objv1alpha1.Object{}
+		if err := e.localCli.Get(ctx, types.NamespacedName{Name: ref.Object.Name}, source); err != nil {
+			return nil, errors.Wrapf(err, "cannot get Object %q", ref.Object.Name)
+		}
+		if len(source.Status.AtProvider.Manifest.Raw) == 0 {
+			return nil, errors.Errorf("Object %q has not observed its remote object yet", ref.Object.Name)
+		}
+		observed := map[string]any{}
+		if err := json.Unmarshal(source.Status.AtProvider.Manifest.Raw, &observed); err != nil {
+			return nil, errors.Wrapf(err, "cannot unmarshal the observed remote object of Object %q", ref.Object.Name)
+		}
+		val, err := fieldpath.Pave(observed).GetValue(ref.Object.FieldPath)
+		return val, errors.Wrapf(err, "cannot get %q field of the remote object observed by Object %q", ref.Object.FieldPath, ref.Object.Name)
+	case ref.ConfigMapKeyRef != nil:
+		cm := &corev1.ConfigMap{}
+		if err := e.localCli.Get(ctx, types.NamespacedName{Namespace: ref.ConfigMapKeyRef.Namespace, Name: ref.ConfigMapKeyRef.Name}, cm); err != nil {
+			return nil, errors.Wrapf(err, "cannot get ConfigMap %s/%s", ref.ConfigMapKeyRef.Namespace, ref.ConfigMapKeyRef.Name)
+		}
+		if val, ok := cm.Data[ref.ConfigMapKeyRef.Key]; ok {
+			return val, nil
+		}
+		if val, ok := cm.BinaryData[ref.ConfigMapKeyRef.Key]; ok {
+			return string(val), nil
+		}
+		return nil, errors.Errorf("ConfigMap %s/%s has no %q key", ref.ConfigMapKeyRef.Namespace, ref.ConfigMapKeyRef.Name, ref.ConfigMapKeyRef.Key)
+	case ref.SecretKeyRef != nil:
+		secret := &corev1.Secret{}
+		if err := e.localCli.Get(ctx, types.NamespacedName{Namespace: ref.SecretKeyRef.Namespace, Name: ref.SecretKeyRef.Name}, secret); err != nil {
+			return nil, errors.Wrapf(err, "cannot get Secret %s/%s", ref.SecretKeyRef.Namespace, ref.SecretKeyRef.Name)
+		}
+		if val, ok := secret.Data[ref.SecretKeyRef.Key]; ok {
+			return string(val), nil
+		}
+		return nil, errors.Errorf("Secret %s/%s has no %q key", ref.SecretKeyRef.Namespace, ref.SecretKeyRef.Name, ref.SecretKeyRef.Key)
+	default:
+		// should never happen, it's validated by the CRD
+		return nil, errors.New("reference has no source")
+	}
+}
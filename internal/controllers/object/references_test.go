@@ -0,0 +1,115 @@
+package object
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+)
+
+func TestDesiredFor(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, objv1alpha1.SchemeBuilder.AddToScheme(scheme))
+
+	service := &objv1alpha1.Object{
+		ObjectMeta: metav1.ObjectMeta{Name: "service"},
+		Status: objv1alpha1.ObjectStatus{
+			AtProvider: objv1alpha1.ObjectObservation{
+				Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"svc"},"spec":{"clusterIP":"10.0.0.1"}}`)},
+			},
+		},
+	}
+	notObserved := &objv1alpha1.Object{ObjectMeta: metav1.ObjectMeta{Name: "not-observed"}}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "ns"},
+		Data:       map[string]string{"key": "cm-value"},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "ns"},
+		Data:       map[string][]byte{"key": []byte("secret-value")},
+	}
+
+	manifest := runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"target","namespace":"default"}}`)}
+
+	tests := []struct {
+		name       string
+		references []objv1alpha1.Reference
+		want       map[string]any
+		wantErr    bool
+	}{
+		{
+			name: "no references",
+		},
+		{
+			name: "all sources",
+			references: []objv1alpha1.Reference{
+				{Object: &objv1alpha1.ObjectFieldSelector{Name: "service", FieldPath: "spec.clusterIP"}, ToFieldPath: "data.ip"},
+				{ConfigMapKeyRef: &objv1alpha1.KeySelector{Name: "cm", Namespace: "ns", Key: "key"}, ToFieldPath: "data.fromCM"},
+				{SecretKeyRef: &objv1alpha1.KeySelector{Name: "secret", Namespace: "ns", Key: "key"}, ToFieldPath: "data.fromSecret"},
+			},
+			want: map[string]any{"ip": "10.0.0.1", "fromCM": "cm-value", "fromSecret": "secret-value"},
+		},
+		{
+			name: "Object not observed yet",
+			references: []objv1alpha1.Reference{
+				{Object: &objv1alpha1.ObjectFieldSelector{Name: "not-observed", FieldPath: "spec.clusterIP"}, ToFieldPath: "data.ip"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing field",
+			references: []objv1alpha1.Reference{
+				{Object: &objv1alpha1.ObjectFieldSelector{Name: "service", FieldPath: "status.loadBalancer.ingress[0].ip"}, ToFieldPath: "data.ip"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing key",
+			references: []objv1alpha1.Reference{
+				{ConfigMapKeyRef: &objv1alpha1.KeySelector{Name: "cm", Namespace: "ns", Key: "other"}, ToFieldPath: "data.fromCM"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing Secret",
+			references: []objv1alpha1.Reference{
+				{SecretKeyRef: &objv1alpha1.KeySelector{Name: "other", Namespace: "ns", Key: "key"}, ToFieldPath: "data.fromSecret"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &external{
+				localCli: fake.NewClientBuilder().WithScheme(scheme).WithObjects([]client.Object{service, notObserved, cm, secret}...).Build(),
+			}
+			cr := &objv1alpha1.Object{
+				Spec: objv1alpha1.ObjectSpec{
+					ForProvider: objv1alpha1.ObjectParameters{Manifest: manifest},
+					References:  tt.references,
+				},
+			}
+
+			desired, err := e.desiredFor(context.Background(), cr)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "target", desired.GetName())
+
+			data, _, err := unstructured.NestedMap(desired.Object, "data")
+			require.NoError(t, err)
+			require.Equal(t, tt.want, data)
+		})
+	}
+}
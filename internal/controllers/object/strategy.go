@@ -0,0 +1,81 @@
+package object
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+)
+
+// create creates the remote object according to the update strategy.
+func (e *external) create(ctx context.Context, desired *unstructured.Unstructured) error {
+	if err := e.checkPolicy(desired); err != nil {
+		return err
+	}
+	switch e.updateStrategy {
+	case objv1alpha1.UpdateStrategyServerSideApply, "":
+		return e.Apply(ctx, desired)
+	case objv1alpha1.UpdateStrategyReplace, objv1alpha1.UpdateStrategyMergePatch, objv1alpha1.UpdateStrategyCreateOnly:
+		return e.remoteCli.Create(ctx, desired, client.FieldOwner(fieldManager(e.applyOpts)))
+	default:
+		return errors.Errorf("unknown update strategy %q", e.updateStrategy)
+	}
+}
+
+// update updates the remote object according to the update strategy.
+func (e *external) update(ctx context.Context, desired *unstructured.Unstructured) error {
+	if err := e.checkPolicy(desired); err != nil {
+		return err
+	}
+	switch e.updateStrategy {
+	case objv1alpha1.UpdateStrategyServerSideApply, "":
+		return e.Apply(ctx, desired)
+	case objv1alpha1.UpdateStrategyReplace:
+		if err := e.setLiveResourceVersion(ctx, desired); err != nil {
+			return err
+		}
+		return e.remoteCli.Update(ctx, desired, client.FieldOwner(fieldManager(e.applyOpts)))
+	case objv1alpha1.UpdateStrategyMergePatch:
+		return e.remoteCli.Patch(ctx, desired, client.Merge, client.FieldOwner(fieldManager(e.applyOpts)))
+	case objv1alpha1.UpdateStrategyCreateOnly:
+		return nil
+	default:
+		return errors.Errorf("unknown update strategy %q", e.updateStrategy)
+	}
+}
+
+// dryRunUpdate updates the remote object in the dry-run mode according to the update strategy, so that desired ends up
+// being the remote object as it would be after the update.
+// It reports false if the update strategy doesn't update existing objects, in which case desired is left untouched.
+func (e *external) dryRunUpdate(ctx context.Context, desired *unstructured.Unstructured) (bool, error) {
+	switch e.updateStrategy {
+	case objv1alpha1.UpdateStrategyServerSideApply, "":
+		return true, e.ApplyDryRun(ctx, desired)
+	case objv1alpha1.UpdateStrategyReplace:
+		if err := e.setLiveResourceVersion(ctx, desired); err != nil {
+			return false, err
+		}
+		return true, e.remoteCli.Update(ctx, desired, client.DryRunAll, client.FieldOwner(fieldManager(e.applyOpts)))
+	case objv1alpha1.UpdateStrategyMergePatch:
+		return true, e.remoteCli.Patch(ctx, desired, client.Merge, client.DryRunAll, client.FieldOwner(fieldManager(e.applyOpts)))
+	case objv1alpha1.UpdateStrategyCreateOnly:
+		return false, nil
+	default:
+		return false, errors.Errorf("unknown update strategy %q", e.updateStrategy)
+	}
+}
+
+// setLiveResourceVersion sets the resource version of the remote object to replace on desired. The live object is read
+// on purpose, the cached one may lag behind and make the update fail due to a conflict.
+func (e *external) setLiveResourceVersion(ctx context.Context, desired *unstructured.Unstructured) error {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(desired.GroupVersionKind())
+	if err := e.remoteCli.Get(ctx, client.ObjectKeyFromObject(desired), current); err != nil {
+		return errors.Wrap(err, "failed to get the remote object to replace")
+	}
+	desired.SetResourceVersion(current.GetResourceVersion())
+	return nil
+}
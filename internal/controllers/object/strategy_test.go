@@ -0,0 +1,143 @@
+package object
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+	"aerf.io/provider-k8s/internal/policy"
+)
+
+func TestUpdateStrategies(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	desiredCM := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": "cm", "namespace": "default"},
+			"data":       map[string]any{"desired": "value"},
+		}}
+	}
+
+	tests := []struct {
+		name          string
+		strategy      objv1alpha1.UpdateStrategy
+		wantData      map[string]string
+		wantUpdatable bool
+	}{
+		{
+			name:          "replace drops the fields missing from the manifest",
+			strategy:      objv1alpha1.UpdateStrategyReplace,
+			wantData:      map[string]string{"desired": "value"},
+			wantUpdatable: true,
+		},
+		{
+			name:          "merge patch keeps the fields missing from the manifest",
+			strategy:      objv1alpha1.UpdateStrategyMergePatch,
+			wantData:      map[string]string{"desired": "value", "existing": "value"},
+			wantUpdatable: true,
+		},
+		{
+			name:     "create only never updates",
+			strategy: objv1alpha1.UpdateStrategyCreateOnly,
+			wantData: map[string]string{"existing": "value"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			remoteCli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"},
+				Data:       map[string]string{"existing": "value"},
+			}).Build()
+			e := &external{remoteCli: remoteCli, updateStrategy: tt.strategy}
+
+			observed := desiredCM()
+			require.NoError(t, remoteCli.Get(ctx, client.ObjectKeyFromObject(observed), observed))
+
+			dryRun := desiredCM()
+			updatable, err := e.dryRunUpdate(ctx, dryRun)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantUpdatable, updatable)
+
+			require.NoError(t, e.update(ctx, desiredCM()))
+
+			got := &corev1.ConfigMap{}
+			require.NoError(t, remoteCli.Get(ctx, client.ObjectKeyFromObject(observed), got))
+			require.Equal(t, tt.wantData, got.Data)
+		})
+	}
+}
+
+func TestReplaceDryRunAfterCacheLag(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	ctx := context.Background()
+	remoteCli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"},
+	}).Build()
+	e := &external{remoteCli: remoteCli, updateStrategy: objv1alpha1.UpdateStrategyReplace}
+
+	// the remote object changes after it's been cached, so the cached resource version is stale
+	live := &corev1.ConfigMap{}
+	require.NoError(t, remoteCli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cm"}, live))
+	cachedResourceVersion := live.GetResourceVersion()
+	live.Data = map[string]string{"changed": "value"}
+	require.NoError(t, remoteCli.Update(ctx, live))
+
+	desired := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "cm", "namespace": "default", "resourceVersion": cachedResourceVersion},
+		"data":       map[string]any{"desired": "value"},
+	}}
+	updatable, err := e.dryRunUpdate(ctx, desired)
+	require.NoError(t, err)
+	require.True(t, updatable)
+	require.Equal(t, live.GetResourceVersion(), desired.GetResourceVersion())
+}
+
+func TestCreateWithoutServerSideApply(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	remoteCli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	e := &external{remoteCli: remoteCli, updateStrategy: objv1alpha1.UpdateStrategyCreateOnly}
+
+	desired := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "cm", "namespace": "default"},
+	}}
+	require.NoError(t, e.create(context.Background(), desired))
+	require.NoError(t, remoteCli.Get(context.Background(), client.ObjectKeyFromObject(desired), &corev1.ConfigMap{}))
+}
+
+func TestCreateDeniedByPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	remoteCli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	e := &external{
+		remoteCli: remoteCli,
+		pcPolicy:  apisv1alpha1.Policy{Deny: []apisv1alpha1.PolicyRule{{Namespaces: []string{"kube-system"}}}},
+	}
+
+	desired := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "cm", "namespace": "kube-system"},
+	}}
+	require.ErrorIs(t, e.create(context.Background(), desired), policy.ErrDenied)
+	require.True(t, apierrors.IsNotFound(remoteCli.Get(context.Background(), client.ObjectKeyFromObject(desired), &corev1.ConfigMap{})))
+}
@@ -0,0 +1,283 @@
+// Package health tells whether Kubernetes objects are ready, using built-in rules for well-known kinds similar to
+// kstatus and Argo CD health checks.
+package health
+
+import (
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Result of a health check.
+type Result struct {
+	Ready bool
+	// Message explains why the object is not ready.
+	Message string
+}
+
+func ready() Result {
+	return Result{Ready: true}
+}
+
+func notReady(format string, args ...any) Result {
+	return Result{Message: fmt.Sprintf(format, args...)}
+}
+
+type checkFn func(obj *unstructured.Unstructured) (Result, error)
+
+var checks = map[schema.GroupKind]checkFn{
+	{Group: "apps", Kind: "Deployment"}:                               typed(checkDeployment),
+	{Group: "apps", Kind: "StatefulSet"}:                              typed(checkStatefulSet),
+	{Group: "apps", Kind: "DaemonSet"}:                                typed(checkDaemonSet),
+	{Group: "apps", Kind: "ReplicaSet"}:                               typed(checkReplicaSet),
+	{Group: "batch", Kind: "Job"}:                                     typed(checkJob),
+	{Group: "", Kind: "Pod"}:                                          typed(checkPod),
+	{Group: "", Kind: "PersistentVolumeClaim"}:                        typed(checkPVC),
+	{Group: "", Kind: "Service"}:                                      typed(checkService),
+	{Group: "", Kind: "Namespace"}:                                    typed(checkNamespace),
+	{Group: "networking.k8s.io", Kind: "Ingress"}:                     typed(checkIngress),
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: typed(checkCRD),
+	{Group: "apiregistration.k8s.io", Kind: "APIService"}:             checkAPIService,
+}
+
+// Check tells whether obj is ready. Kinds without a built-in rule are ready once their status describes their latest
+// generation and their Ready condition, if any, is True.
+func Check(obj *unstructured.Unstructured) (Result, error) {
+	if check, ok := checks[obj.GroupVersionKind().GroupKind()]; ok {
+		return check(obj)
+	}
+	return checkGeneric(obj)
+}
+
+// typed converts the object to the type of the check before running it.
+func typed[T any](check func(*T) Result) checkFn {
+	return func(obj *unstructured.Unstructured) (Result, error) {
+		typedObj := new(T)
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), typedObj); err != nil {
+			return Result{}, errors.Wrapf(err, "cannot convert %s to its type", obj.GroupVersionKind().Kind)
+		}
+		return check(typedObj), nil
+	}
+}
+
+func generationObserved(meta metav1.ObjectMeta, observedGeneration int64) bool {
+	return observedGeneration >= meta.Generation
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+func checkDeployment(d *appsv1.Deployment) Result {
+	if !generationObserved(d.ObjectMeta, d.Status.ObservedGeneration) {
+		return notReady("Deployment's generation %d is not observed yet", d.Generation)
+	}
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Reason == "ProgressDeadlineExceeded" {
+			return notReady("Deployment exceeded its progress deadline: %s", c.Message)
+		}
+	}
+	replicas := replicasOrDefault(d.Spec.Replicas)
+	switch {
+	case d.Status.UpdatedReplicas < replicas:
+		return notReady("Updated replicas: %d/%d", d.Status.UpdatedReplicas, replicas)
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		return notReady("Old replicas pending termination: %d", d.Status.Replicas-d.Status.UpdatedReplicas)
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		return notReady("Available replicas: %d/%d", d.Status.AvailableReplicas, d.Status.UpdatedReplicas)
+	}
+	return ready()
+}
+
+func checkStatefulSet(s *appsv1.StatefulSet) Result {
+	if !generationObserved(s.ObjectMeta, s.Status.ObservedGeneration) {
+		return notReady("StatefulSet's generation %d is not observed yet", s.Generation)
+	}
+	replicas := replicasOrDefault(s.Spec.Replicas)
+	if s.Status.ReadyReplicas < replicas {
+		return notReady("Ready replicas: %d/%d", s.Status.ReadyReplicas, replicas)
+	}
+	if s.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return ready()
+	}
+	if ru := s.Spec.UpdateStrategy.RollingUpdate; ru != nil && ru.Partition != nil && *ru.Partition > 0 {
+		if want := replicas - *ru.Partition; s.Status.UpdatedReplicas < want {
+			return notReady("Updated replicas: %d/%d", s.Status.UpdatedReplicas, want)
+		}
+		return ready()
+	}
+	if s.Status.UpdateRevision != s.Status.CurrentRevision {
+		return notReady("Rolling out revision %s, updated replicas: %d/%d", s.Status.UpdateRevision, s.Status.UpdatedReplicas, replicas)
+	}
+	return ready()
+}
+
+func checkDaemonSet(d *appsv1.DaemonSet) Result {
+	if !generationObserved(d.ObjectMeta, d.Status.ObservedGeneration) {
+		return notReady("DaemonSet's generation %d is not observed yet", d.Generation)
+	}
+	switch {
+	case d.Status.UpdatedNumberScheduled < d.Status.DesiredNumberScheduled:
+		return notReady("Updated pods: %d/%d", d.Status.UpdatedNumberScheduled, d.Status.DesiredNumberScheduled)
+	case d.Status.NumberAvailable < d.Status.DesiredNumberScheduled:
+		return notReady("Available pods: %d/%d", d.Status.NumberAvailable, d.Status.DesiredNumberScheduled)
+	}
+	return ready()
+}
+
+func checkReplicaSet(r *appsv1.ReplicaSet) Result {
+	if !generationObserved(r.ObjectMeta, r.Status.ObservedGeneration) {
+		return notReady("ReplicaSet's generation %d is not observed yet", r.Generation)
+	}
+	if replicas := replicasOrDefault(r.Spec.Replicas); r.Status.AvailableReplicas < replicas {
+		return notReady("Available replicas: %d/%d", r.Status.AvailableReplicas, replicas)
+	}
+	return ready()
+}
+
+func checkJob(j *batchv1.Job) Result {
+	for _, c := range j.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type { //nolint:exhaustive // other conditions don't tell whether the Job is done
+		case batchv1.JobComplete:
+			return ready()
+		case batchv1.JobFailed:
+			return notReady("Job failed: %s", c.Message)
+		}
+	}
+	return notReady("Job is not complete yet, active pods: %d", j.Status.Active)
+}
+
+func checkPod(p *corev1.Pod) Result {
+	switch p.Status.Phase {
+	case corev1.PodSucceeded:
+		return ready()
+	case corev1.PodFailed:
+		return notReady("Pod failed: %s", p.Status.Message)
+	case corev1.PodRunning:
+		for _, c := range p.Status.Conditions {
+			if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+				return ready()
+			}
+		}
+		return notReady("Pod is running but not ready")
+	default:
+		return notReady("Pod is %s", p.Status.Phase)
+	}
+}
+
+func checkPVC(p *corev1.PersistentVolumeClaim) Result {
+	if p.Status.Phase != corev1.ClaimBound {
+		return notReady("PersistentVolumeClaim is %s", p.Status.Phase)
+	}
+	return ready()
+}
+
+func checkService(s *corev1.Service) Result {
+	if s.Spec.Type == corev1.ServiceTypeLoadBalancer && len(s.Status.LoadBalancer.Ingress) == 0 {
+		return notReady("Service's load balancer is not provisioned yet")
+	}
+	return ready()
+}
+
+func checkNamespace(n *corev1.Namespace) Result {
+	if n.Status.Phase != corev1.NamespaceActive {
+		return notReady("Namespace is %s", n.Status.Phase)
+	}
+	return ready()
+}
+
+func checkIngress(i *networkingv1.Ingress) Result {
+	if len(i.Status.LoadBalancer.Ingress) == 0 {
+		return notReady("Ingress's load balancer is not provisioned yet")
+	}
+	return ready()
+}
+
+func checkCRD(c *extv1.CustomResourceDefinition) Result {
+	for _, cond := range c.Status.Conditions {
+		if cond.Type == extv1.NamesAccepted && cond.Status == extv1.ConditionFalse {
+			return notReady("CustomResourceDefinition's names are not accepted: %s", cond.Message)
+		}
+	}
+	for _, cond := range c.Status.Conditions {
+		if cond.Type == extv1.Established && cond.Status == extv1.ConditionTrue {
+			return ready()
+		}
+	}
+	return notReady("CustomResourceDefinition is not established yet")
+}
+
+// checkAPIService doesn't use the typed APIService, as the kube-aggregator module is not worth depending on just for it.
+func checkAPIService(obj *unstructured.Unstructured) (Result, error) {
+	conditions, err := conditionsOf(obj)
+	if err != nil {
+		return Result{}, err
+	}
+	available, ok := conditions["Available"]
+	switch {
+	case ok && available.Status == metav1.ConditionTrue:
+		return ready(), nil
+	case ok && available.Message != "":
+		return notReady("APIService is not available: %s", available.Message), nil
+	default:
+		return notReady("APIService is not available yet"), nil
+	}
+}
+
+func checkGeneric(obj *unstructured.Unstructured) (Result, error) {
+	observedGeneration, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err != nil {
+		return Result{}, errors.Wrap(err, "cannot read status.observedGeneration")
+	}
+	if found && observedGeneration < obj.GetGeneration() {
+		return notReady("%s's generation %d is not observed yet", obj.GetKind(), obj.GetGeneration()), nil
+	}
+
+	conditions, err := conditionsOf(obj)
+	if err != nil {
+		return Result{}, err
+	}
+	if c, ok := conditions["Ready"]; ok && c.Status != metav1.ConditionTrue {
+		if c.Message != "" {
+			return notReady("%s is not ready: %s", obj.GetKind(), c.Message), nil
+		}
+		return notReady("%s's Ready condition is %s", obj.GetKind(), c.Status), nil
+	}
+	return ready(), nil
+}
+
+func conditionsOf(obj *unstructured.Unstructured) (map[string]metav1.Condition, error) {
+	raw, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read status.conditions")
+	}
+	conditions := make(map[string]metav1.Condition, len(raw))
+	for _, r := range raw {
+		m, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		c := metav1.Condition{}
+		c.Type, _, _ = unstructured.NestedString(m, "type")
+		status, _, _ := unstructured.NestedString(m, "status")
+		c.Status = metav1.ConditionStatus(status)
+		c.Message, _, _ = unstructured.NestedString(m, "message")
+		conditions[c.Type] = c
+	}
+	return conditions, nil
+}
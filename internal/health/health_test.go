@@ -0,0 +1,192 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  string
+		want Result
+	}{
+		{
+			name: "rolled out Deployment",
+			obj: `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {generation: 2}
+spec: {replicas: 2}
+status: {observedGeneration: 2, replicas: 2, updatedReplicas: 2, availableReplicas: 2}`,
+			want: Result{Ready: true},
+		},
+		{
+			name: "Deployment with unobserved generation",
+			obj: `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {generation: 3}
+status: {observedGeneration: 2, replicas: 1, updatedReplicas: 1, availableReplicas: 1}`,
+			want: Result{Message: "Deployment's generation 3 is not observed yet"},
+		},
+		{
+			name: "Deployment with old replicas",
+			obj: `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {generation: 1}
+spec: {replicas: 1}
+status: {observedGeneration: 1, replicas: 2, updatedReplicas: 1, availableReplicas: 1}`,
+			want: Result{Message: "Old replicas pending termination: 1"},
+		},
+		{
+			name: "Deployment past its progress deadline",
+			obj: `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {generation: 1}
+status:
+  observedGeneration: 1
+  conditions: [{type: Progressing, status: "False", reason: ProgressDeadlineExceeded, message: timed out}]`,
+			want: Result{Message: "Deployment exceeded its progress deadline: timed out"},
+		},
+		{
+			name: "StatefulSet rolling out",
+			obj: `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata: {generation: 1}
+spec: {replicas: 2}
+status: {observedGeneration: 1, readyReplicas: 2, updatedReplicas: 1, currentRevision: a, updateRevision: b}`,
+			want: Result{Message: "Rolling out revision b, updated replicas: 1/2"},
+		},
+		{
+			name: "DaemonSet with unavailable pods",
+			obj: `
+apiVersion: apps/v1
+kind: DaemonSet
+status: {desiredNumberScheduled: 3, updatedNumberScheduled: 3, numberAvailable: 2}`,
+			want: Result{Message: "Available pods: 2/3"},
+		},
+		{
+			name: "ReplicaSet",
+			obj: `
+apiVersion: apps/v1
+kind: ReplicaSet
+spec: {replicas: 2}
+status: {availableReplicas: 2}`,
+			want: Result{Ready: true},
+		},
+		{
+			name: "failed Job",
+			obj: `
+apiVersion: batch/v1
+kind: Job
+status: {conditions: [{type: Failed, status: "True", message: BackoffLimitExceeded}]}`,
+			want: Result{Message: "Job failed: BackoffLimitExceeded"},
+		},
+		{
+			name: "complete Job",
+			obj: `
+apiVersion: batch/v1
+kind: Job
+status: {conditions: [{type: Complete, status: "True"}]}`,
+			want: Result{Ready: true},
+		},
+		{
+			name: "running Pod",
+			obj: `
+apiVersion: v1
+kind: Pod
+status: {phase: Running, conditions: [{type: Ready, status: "True"}]}`,
+			want: Result{Ready: true},
+		},
+		{
+			name: "pending PVC",
+			obj: `
+apiVersion: v1
+kind: PersistentVolumeClaim
+status: {phase: Pending}`,
+			want: Result{Message: "PersistentVolumeClaim is Pending"},
+		},
+		{
+			name: "ClusterIP Service",
+			obj: `
+apiVersion: v1
+kind: Service
+spec: {type: ClusterIP}`,
+			want: Result{Ready: true},
+		},
+		{
+			name: "LoadBalancer Service without ingress",
+			obj: `
+apiVersion: v1
+kind: Service
+spec: {type: LoadBalancer}`,
+			want: Result{Message: "Service's load balancer is not provisioned yet"},
+		},
+		{
+			name: "terminating Namespace",
+			obj: `
+apiVersion: v1
+kind: Namespace
+status: {phase: Terminating}`,
+			want: Result{Message: "Namespace is Terminating"},
+		},
+		{
+			name: "Ingress with load balancer",
+			obj: `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+status: {loadBalancer: {ingress: [{ip: 10.0.0.1}]}}`,
+			want: Result{Ready: true},
+		},
+		{
+			name: "established CRD",
+			obj: `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+status: {conditions: [{type: NamesAccepted, status: "True"}, {type: Established, status: "True"}]}`,
+			want: Result{Ready: true},
+		},
+		{
+			name: "unavailable APIService",
+			obj: `
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+status: {conditions: [{type: Available, status: "False", message: service not found}]}`,
+			want: Result{Message: "APIService is not available: service not found"},
+		},
+		{
+			name: "ConfigMap falls back to the generic rule",
+			obj: `
+apiVersion: v1
+kind: ConfigMap`,
+			want: Result{Ready: true},
+		},
+		{
+			name: "custom resource with a Ready condition",
+			obj: `
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata: {generation: 1}
+status: {observedGeneration: 1, conditions: [{type: Ready, status: "False", message: Issuing certificate}]}`,
+			want: Result{Message: "Certificate is not ready: Issuing certificate"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := yaml.YAMLToJSON([]byte(tt.obj))
+			require.NoError(t, err)
+			obj := &unstructured.Unstructured{}
+			require.NoError(t, obj.UnmarshalJSON(raw))
+			got, err := Check(obj)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
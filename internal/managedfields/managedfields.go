@@ -0,0 +1,156 @@
+// Package managedfields works with the field sets stored in objects' metadata.managedFields.
+package managedfields
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// OwnedBy returns the fields of obj owned by any of the given field managers.
+func OwnedBy(obj metav1.Object, managers ...string) (*fieldpath.Set, error) {
+	owned := &fieldpath.Set{}
+	for _, entry := range obj.GetManagedFields() {
+		if !slices.Contains(managers, entry.Manager) || entry.FieldsV1 == nil {
+			continue
+		}
+		set := &fieldpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, errors.Wrapf(err, "cannot parse the fields managed by %q", entry.Manager)
+		}
+		owned = owned.Union(set)
+	}
+	return owned, nil
+}
+
+// Remove removes the leaf fields of set from the unstructured content. Fields missing from the content are skipped.
+func Remove(content map[string]any, set *fieldpath.Set) {
+	set.Leaves().Iterate(func(p fieldpath.Path) {
+		remove(content, p)
+	})
+}
+
+// Filter returns the unstructured content limited to the fields of set. Fields that are members of set without children,
+// e.g. atomic lists, are kept as a whole. The kept values are shared with the content rather than copied.
+func Filter(content map[string]any, set *fieldpath.Set) map[string]any {
+	filtered, _ := filter(content, set).(map[string]any)
+	return filtered
+}
+
+func filter(node any, set *fieldpath.Set) any {
+	switch n := node.(type) {
+	case map[string]any:
+		filtered := make(map[string]any)
+		for k, v := range n {
+			if kept, ok := filterElement(v, fieldpath.PathElement{FieldName: &k}, set); ok {
+				filtered[k] = kept
+			}
+		}
+		return filtered
+	case []any:
+		elems := map[int]fieldpath.PathElement{}
+		collect := func(pe fieldpath.PathElement) {
+			if i := indexOf(n, pe); i >= 0 {
+				elems[i] = pe
+			}
+		}
+		set.Members.Iterate(collect)
+		set.Children.Iterate(collect)
+
+		filtered := make([]any, 0, len(elems))
+		for i, elem := range n {
+			pe, ok := elems[i]
+			if !ok {
+				continue
+			}
+			if kept, ok := filterElement(elem, pe, set); ok {
+				filtered = append(filtered, kept)
+			}
+		}
+		return filtered
+	default:
+		return node
+	}
+}
+
+func filterElement(v any, pe fieldpath.PathElement, set *fieldpath.Set) (any, bool) {
+	if children, ok := set.Children.Get(pe); ok {
+		return filter(v, children), true
+	}
+	if set.Members.Has(pe) {
+		return v, true
+	}
+	return nil, false
+}
+
+func remove(node any, p fieldpath.Path) any {
+	if len(p) == 0 {
+		return node
+	}
+	if p[0].FieldName != nil {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return node
+		}
+		name := *p[0].FieldName
+		if len(p) == 1 {
+			delete(m, name)
+			return m
+		}
+		if child, ok := m[name]; ok {
+			m[name] = remove(child, p[1:])
+		}
+		return m
+	}
+
+	l, ok := node.([]any)
+	if !ok {
+		return node
+	}
+	i := indexOf(l, p[0])
+	if i < 0 {
+		return l
+	}
+	if len(p) == 1 {
+		return append(l[:i:i], l[i+1:]...)
+	}
+	l[i] = remove(l[i], p[1:])
+	return l
+}
+
+// indexOf returns the index of the list element identified by pe, or -1 if there's no such element.
+func indexOf(l []any, pe fieldpath.PathElement) int {
+	switch {
+	case pe.Index != nil:
+		if *pe.Index < len(l) {
+			return *pe.Index
+		}
+	case pe.Key != nil:
+		for i, elem := range l {
+			if m, ok := elem.(map[string]any); ok && matchesKey(m, *pe.Key) {
+				return i
+			}
+		}
+	case pe.Value != nil:
+		for i, elem := range l {
+			if value.Equals(value.NewValueInterface(elem), *pe.Value) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func matchesKey(m map[string]any, key value.FieldList) bool {
+	for _, field := range key {
+		v, ok := m[field.Name]
+		if !ok || !value.Equals(value.NewValueInterface(v), field.Value) {
+			return false
+		}
+	}
+	return true
+}
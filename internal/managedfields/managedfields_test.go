@@ -0,0 +1,98 @@
+package managedfields
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+func TestRemoveOwnedBy(t *testing.T) {
+	newDeploy := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name":        "deploy",
+				"annotations": map[string]any{"deployment.kubernetes.io/revision": "3", "owner": "me"},
+			},
+			"spec": map[string]any{
+				"replicas": int64(3),
+				"template": map[string]any{"spec": map[string]any{"containers": []any{
+					map[string]any{"name": "nginx", "image": "nginx:1.25", "args": []any{"a", "b"}},
+					map[string]any{"name": "sidecar", "image": "sidecar:1"},
+				}}},
+			},
+		}}
+	}
+
+	obj := newDeploy()
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:  "kube-controller-manager",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{"f:deployment.kubernetes.io/revision":{}}},"f:spec":{"f:replicas":{}}}`)},
+		},
+		{
+			Manager:  "injector",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"sidecar\"}":{".":{},"f:image":{},"f:name":{}},"k:{\"name\":\"nginx\"}":{"f:args":{"v:\"b\"":{}}}}}}}}`)},
+		},
+		{
+			Manager:  "provider-k8s",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{"f:owner":{}}}}`)},
+		},
+	})
+
+	owned, err := OwnedBy(obj, "kube-controller-manager", "injector")
+	require.NoError(t, err)
+
+	Remove(obj.Object, owned)
+
+	want := newDeploy()
+	want.SetAnnotations(map[string]string{"owner": "me"})
+	unstructured.RemoveNestedField(want.Object, "spec", "replicas")
+	require.NoError(t, unstructured.SetNestedSlice(want.Object, []any{
+		map[string]any{"name": "nginx", "image": "nginx:1.25", "args": []any{"a"}},
+		map[string]any{},
+	}, "spec", "template", "spec", "containers"))
+	obj.SetManagedFields(nil)
+	require.Equal(t, want.Object, obj.Object)
+}
+
+func TestOwnedByInvalidFields(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "broken", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"k:notjson":{}}`)}}})
+	_, err := OwnedBy(obj, "broken")
+	require.Error(t, err)
+}
+
+func TestFilter(t *testing.T) {
+	content := map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]any{
+			"name":        "deploy",
+			"labels":      map[string]any{"app": "deploy"},
+			"annotations": map[string]any{"deployment.kubernetes.io/revision": "3"},
+		},
+		"spec": map[string]any{
+			"replicas": int64(3),
+			"template": map[string]any{"spec": map[string]any{"containers": []any{
+				map[string]any{"name": "sidecar", "image": "sidecar:1"},
+				map[string]any{"name": "nginx", "image": "nginx:1.25", "args": []any{"a", "b"}, "imagePullPolicy": "Always"},
+			}}},
+		},
+		"status": map[string]any{"replicas": int64(3)},
+	}
+	set := &fieldpath.Set{}
+	require.NoError(t, set.FromJSON(strings.NewReader(`{"f:metadata":{"f:labels":{"f:app":{}}},"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"nginx\"}":{".":{},"f:args":{},"f:image":{},"f:name":{}}}}}}}`)))
+
+	require.Equal(t, map[string]any{
+		"metadata": map[string]any{"labels": map[string]any{"app": "deploy"}},
+		"spec": map[string]any{"template": map[string]any{"spec": map[string]any{"containers": []any{
+			map[string]any{"name": "nginx", "image": "nginx:1.25", "args": []any{"a", "b"}},
+		}}}},
+	}, Filter(content, set))
+}
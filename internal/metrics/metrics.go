@@ -0,0 +1,159 @@
+// Package metrics defines the provider's Prometheus metrics, exposed through the controller-runtime metrics registry.
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "provider_k8s"
+
+// Remote request operations.
+const (
+	OperationApply  = "apply"
+	OperationDryRun = "dry_run"
+	OperationDelete = "delete"
+)
+
+var objectLabels = []string{"group", "version", "kind", "provider_config"}
+
+var (
+	// DriftDetected counts the remote objects found to be drifted from their manifests.
+	DriftDetected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "drift_detected_total",
+		Help:      "Number of times a remote object has been found to be drifted from its manifest.",
+	}, objectLabels)
+
+	// DriftCorrected counts the drifted remote objects updated to match their manifests.
+	DriftCorrected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "drift_corrected_total",
+		Help:      "Number of times a drifted remote object has been updated to match its manifest.",
+	}, objectLabels)
+
+	// ApplyConflicts counts the server-side apply conflicts with other field managers.
+	ApplyConflicts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "apply_conflicts_total",
+		Help:      "Number of server-side applies that conflicted with other field managers, by whether the conflicting fields were forced.",
+	}, append(objectLabels, "forced"))
+
+	// ReadinessErrors counts the failures to evaluate the readiness of remote objects.
+	ReadinessErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "readiness_evaluation_errors_total",
+		Help:      "Number of failures to evaluate the readiness of a remote object.",
+	}, []string{"group", "version", "kind", "policy"})
+
+	// RemoteRequestDuration observes the latency of the requests changing remote objects.
+	RemoteRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "remote_request_duration_seconds",
+		Help:      "Latency of apply, dry-run and delete requests sent to remote API servers.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "host"})
+
+	// ActiveCaches is the number of remote object caches running per remote cluster.
+	ActiveCaches = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_caches",
+		Help:      "Number of remote object caches running per remote API server.",
+	}, []string{"host"})
+
+	// CELProgramCacheHits counts the CEL evaluations that reused an already compiled program.
+	CELProgramCacheHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cel_program_cache_hits_total",
+		Help:      "Number of CEL evaluations that reused an already compiled program.",
+	})
+
+	// CELProgramCacheMisses counts the CEL evaluations that had to compile their program.
+	CELProgramCacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cel_program_cache_misses_total",
+		Help:      "Number of CEL evaluations that had to compile their program.",
+	})
+
+	// CELCompileDuration observes the time it takes to compile and plan a CEL program.
+	CELCompileDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "cel_compile_duration_seconds",
+		Help:      "Time it takes to compile and plan a CEL program, including failed compilations.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		DriftDetected,
+		DriftCorrected,
+		ApplyConflicts,
+		ReadinessErrors,
+		RemoteRequestDuration,
+		ActiveCaches,
+		CELProgramCacheHits,
+		CELProgramCacheMisses,
+		CELCompileDuration,
+	)
+}
+
+// ObjectLabels returns the values of the labels identifying remote objects of the given kind managed via the given ProviderConfig.
+func ObjectLabels(gvk schema.GroupVersionKind, providerConfig string) prometheus.Labels {
+	return prometheus.Labels{"group": gvk.Group, "version": gvk.Version, "kind": gvk.Kind, "provider_config": providerConfig}
+}
+
+// ConflictLabels returns the labels of ApplyConflicts.
+func ConflictLabels(gvk schema.GroupVersionKind, providerConfig string, forced bool) prometheus.Labels {
+	labels := ObjectLabels(gvk, providerConfig)
+	labels["forced"] = strconv.FormatBool(forced)
+	return labels
+}
+
+// InstrumentClient wraps the client of the remote API server at host, so that the latency of its create, update, patch
+// and delete requests is observed in RemoteRequestDuration.
+func InstrumentClient(c client.Client, host string) client.Client {
+	return &instrumentedClient{Client: c, host: host}
+}
+
+type instrumentedClient struct {
+	client.Client
+	host string
+}
+
+func (c *instrumentedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	defer c.observe(writeOperation((&client.CreateOptions{}).ApplyOptions(opts).DryRun), time.Now())
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *instrumentedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	defer c.observe(writeOperation((&client.UpdateOptions{}).ApplyOptions(opts).DryRun), time.Now())
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *instrumentedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	defer c.observe(writeOperation((&client.PatchOptions{}).ApplyOptions(opts).DryRun), time.Now())
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *instrumentedClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	defer c.observe(OperationDelete, time.Now())
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *instrumentedClient) observe(operation string, start time.Time) {
+	RemoteRequestDuration.WithLabelValues(operation, c.host).Observe(time.Since(start).Seconds())
+}
+
+func writeOperation(dryRun []string) string {
+	if len(dryRun) > 0 {
+		return OperationDryRun
+	}
+	return OperationApply
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestInstrumentClient(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	const host = "https://remote.example.com"
+	c := InstrumentClient(fake.NewClientBuilder().WithScheme(scheme).Build(), host)
+	ctx := context.Background()
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}
+	require.NoError(t, c.Create(ctx, cm.DeepCopy(), client.DryRunAll))
+	require.NoError(t, c.Create(ctx, cm.DeepCopy()))
+	require.NoError(t, c.Update(ctx, cm.DeepCopy()))
+	require.NoError(t, c.Delete(ctx, cm.DeepCopy()))
+
+	require.Equal(t, 3, testutil.CollectAndCount(RemoteRequestDuration, "provider_k8s_remote_request_duration_seconds"))
+	for operation, want := range map[string]uint64{OperationApply: 2, OperationDryRun: 1, OperationDelete: 1} {
+		m := &dto.Metric{}
+		require.NoError(t, RemoteRequestDuration.WithLabelValues(operation, host).(prometheus.Histogram).Write(m))
+		require.Equal(t, want, m.GetHistogram().GetSampleCount(), operation)
+	}
+}
@@ -0,0 +1,135 @@
+// Package objectindex indexes Objects and ObjectSets by the remote objects they manage, so that no remote object is
+// managed twice.
+package objectindex
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+)
+
+// Field is the name of the index of Objects and ObjectSets by the remote objects they manage.
+const Field = "remoteObject"
+
+// defaultNamespace is the namespace of the namespaced remote objects whose manifests have none, as in the cache registry.
+const defaultNamespace = "default"
+
+// Key returns the index key of the remote object on the API server at host. The version of the remote object's kind
+// is left out, as every version of a kind serves the same objects. An empty namespace is keyed as the default one, so
+// that the manifests of a namespaced remote object with and without it collide. The scope of the kind isn't known
+// here, which is fine since every key of a cluster-scoped kind is then keyed the same way.
+func Key(host string, remote *unstructured.Unstructured) string {
+	namespace := remote.GetNamespace()
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	return strings.Join([]string{
+		strings.TrimSuffix(host, "/"),
+		remote.GroupVersionKind().GroupKind().String(),
+		namespace,
+		remote.GetName(),
+	}, "|")
+}
+
+// IndexObject indexes the Object by the remote object it manages. Objects that haven't been observed yet, so whose
+// remote API server isn't known, aren't indexed.
+func IndexObject(obj client.Object) []string {
+	o, ok := obj.(*objv1alpha1.Object)
+	if !ok || o.Status.AtProvider.Host == "" {
+		return nil
+	}
+	desired, err := o.GetDesired()
+	if err != nil {
+		return nil
+	}
+	return []string{Key(o.Status.AtProvider.Host, desired)}
+}
+
+// IndexObjectSet indexes the ObjectSet by every remote object it manages. ObjectSets that haven't been observed yet
+// aren't indexed, nor are their malformed manifests.
+func IndexObjectSet(obj client.Object) []string {
+	s, ok := obj.(*objv1alpha1.ObjectSet)
+	if !ok || s.Status.AtProvider.Host == "" {
+		return nil
+	}
+	keys := make([]string, 0, len(s.Spec.ForProvider.Manifests))
+	for _, m := range s.Spec.ForProvider.Manifests {
+		desired := &unstructured.Unstructured{}
+		if err := desired.UnmarshalJSON(m.Manifest.Raw); err != nil {
+			continue
+		}
+		keys = append(keys, Key(s.Status.AtProvider.Host, desired))
+	}
+	return keys
+}
+
+// Setup adds the indexes to the indexer.
+func Setup(ctx context.Context, indexer client.FieldIndexer) error {
+	if err := indexer.IndexField(ctx, &objv1alpha1.Object{}, Field, IndexObject); err != nil {
+		return errors.Wrap(err, "cannot index Objects by their remote objects")
+	}
+	return errors.Wrap(indexer.IndexField(ctx, &objv1alpha1.ObjectSet{}, Field, IndexObjectSet), "cannot index ObjectSets by their remote objects")
+}
+
+// Owner returns the Object or ObjectSet managing the remote object on the API server at host, which the given Object
+// or ObjectSet targets as well, e.g. `ObjectSet "name"`. The owner is the oldest of them, the not yet created ones
+// being the newest. It returns an empty string if the given one is the owner. The reader must serve the indexes.
+func Owner(ctx context.Context, r client.Reader, o client.Object, host string, remote *unstructured.Unstructured) (string, error) {
+	objects := &objv1alpha1.ObjectList{}
+	if err := r.List(ctx, objects, client.MatchingFields{Field: Key(host, remote)}); err != nil {
+		return "", errors.Wrap(err, "cannot list Objects managing the same remote object")
+	}
+	sets := &objv1alpha1.ObjectSetList{}
+	if err := r.List(ctx, sets, client.MatchingFields{Field: Key(host, remote)}); err != nil {
+		return "", errors.Wrap(err, "cannot list ObjectSets managing the same remote object")
+	}
+	others := make([]client.Object, 0, len(objects.Items)+len(sets.Items))
+	for i := range objects.Items {
+		others = append(others, &objects.Items[i])
+	}
+	for i := range sets.Items {
+		others = append(others, &sets.Items[i])
+	}
+
+	owner := o
+	for _, other := range others {
+		if !isSame(other, o) && isOlder(other, owner) {
+			owner = other
+		}
+	}
+	if owner == o {
+		return "", nil
+	}
+	return fmt.Sprintf("%s %q", kindOf(owner), owner.GetName()), nil
+}
+
+func kindOf(obj client.Object) string {
+	if _, ok := obj.(*objv1alpha1.ObjectSet); ok {
+		return objv1alpha1.ObjectSetKind
+	}
+	return objv1alpha1.ObjectKind
+}
+
+func isSame(a, b client.Object) bool {
+	return kindOf(a) == kindOf(b) && a.GetName() == b.GetName()
+}
+
+func isOlder(a, b client.Object) bool {
+	aCreated, bCreated := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	switch {
+	case aCreated.Equal(&bCreated):
+		return a.GetName() < b.GetName()
+	case bCreated.IsZero():
+		return true
+	case aCreated.IsZero():
+		return false
+	default:
+		return aCreated.Before(&bCreated)
+	}
+}
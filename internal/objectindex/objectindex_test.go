@@ -0,0 +1,139 @@
+package objectindex
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+)
+
+func TestOwner(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, objv1alpha1.SchemeBuilder.AddToScheme(scheme))
+
+	const host = "https://remote.example.com"
+	now := time.Now()
+	object := func(name, host, manifest string, created time.Time) *objv1alpha1.Object {
+		o := &objv1alpha1.Object{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Spec: objv1alpha1.ObjectSpec{
+				ForProvider: objv1alpha1.ObjectParameters{Manifest: runtime.RawExtension{Raw: []byte(manifest)}},
+			},
+		}
+		o.Status.AtProvider.Host = host
+		return o
+	}
+	objectSet := func(name, host, manifest string, created time.Time) *objv1alpha1.ObjectSet {
+		s := &objv1alpha1.ObjectSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Spec: objv1alpha1.ObjectSetSpec{
+				ForProvider: objv1alpha1.ObjectSetParameters{Manifests: []objv1alpha1.ObjectSetManifest{
+					{Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"default"}}`)}},
+					{Manifest: runtime.RawExtension{Raw: []byte(manifest)}},
+				}},
+			},
+		}
+		s.Status.AtProvider.Host = host
+		return s
+	}
+	const (
+		deployV1 = `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"deploy","namespace":"default"}}`
+		deployV2 = `{"apiVersion":"apps/v2","kind":"Deployment","metadata":{"name":"deploy","namespace":"default"}}`
+		otherNS  = `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"deploy","namespace":"other"}}`
+		noNS     = `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"deploy"}}`
+	)
+
+	tests := []struct {
+		name    string
+		objects []client.Object
+		o       *objv1alpha1.Object
+		want    string
+	}{
+		{
+			name: "only Object",
+			o:    object("new", host, deployV1, now),
+		},
+		{
+			name:    "older Object owns the remote object",
+			objects: []client.Object{object("old", host, deployV1, now.Add(-time.Hour))},
+			o:       object("new", host, deployV1, now),
+			want:    `Object "old"`,
+		},
+		{
+			name:    "version of the kind doesn't matter",
+			objects: []client.Object{object("old", host+"/", deployV2, now.Add(-time.Hour))},
+			o:       object("new", host, deployV1, now),
+			want:    `Object "old"`,
+		},
+		{
+			name:    "not yet created Object is the newest",
+			objects: []client.Object{object("old", host, deployV1, now)},
+			o:       object("new", "", deployV1, time.Time{}),
+			want:    `Object "old"`,
+		},
+		{
+			name:    "Object is the owner",
+			objects: []client.Object{object("new", host, deployV1, now)},
+			o:       object("old", host, deployV1, now.Add(-time.Hour)),
+		},
+		{
+			name:    "same creation time",
+			objects: []client.Object{object("a", host, deployV1, now)},
+			o:       object("b", host, deployV1, now),
+			want:    `Object "a"`,
+		},
+		{
+			name:    "empty namespace is the default one",
+			objects: []client.Object{object("old", host, noNS, now.Add(-time.Hour))},
+			o:       object("new", host, deployV1, now),
+			want:    `Object "old"`,
+		},
+		{
+			name:    "older ObjectSet owns the remote object",
+			objects: []client.Object{objectSet("old", host, deployV1, now.Add(-time.Hour))},
+			o:       object("new", host, deployV1, now),
+			want:    `ObjectSet "old"`,
+		},
+		{
+			name:    "ObjectSet of the same name is another owner",
+			objects: []client.Object{objectSet("new", host, deployV1, now.Add(-time.Hour))},
+			o:       object("new", host, deployV1, now),
+			want:    `ObjectSet "new"`,
+		},
+		{
+			name:    "Object is older than the ObjectSet",
+			objects: []client.Object{objectSet("old", host, deployV1, now)},
+			o:       object("new", host, deployV1, now.Add(-time.Hour)),
+		},
+		{
+			name: "other remote objects",
+			objects: []client.Object{
+				object("other-host", "https://other.example.com", deployV1, now.Add(-time.Hour)),
+				object("other-namespace", host, otherNS, now.Add(-time.Hour)),
+				object("not-observed", "", deployV1, now.Add(-time.Hour)),
+				objectSet("other-objectset", host, otherNS, now.Add(-time.Hour)),
+			},
+			o: object("new", host, deployV1, now),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).WithIndex(&objv1alpha1.Object{}, Field, IndexObject).
+				WithIndex(&objv1alpha1.ObjectSet{}, Field, IndexObjectSet).
+				Build()
+			desired, err := tt.o.GetDesired()
+			require.NoError(t, err)
+
+			owner, err := Owner(context.Background(), r, tt.o, host, desired)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, owner)
+		})
+	}
+}
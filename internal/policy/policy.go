@@ -0,0 +1,75 @@
+// Package policy enforces the policies of ProviderConfigs, restricting the remote objects managed via them.
+package policy
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+	"aerf.io/provider-k8s/internal/celcheck"
+)
+
+// ErrDenied is returned when the policy doesn't allow managing the remote object.
+var ErrDenied = errors.New("denied by the ProviderConfig's policy")
+
+// CELVariables are the top-level fields of the remote objects declared as variables of the policies' CEL expressions,
+// whichever fields the remote object has.
+var CELVariables = []string{"apiVersion", "kind", "metadata", "spec", "status"}
+
+// Check returns an error wrapping ErrDenied if the policy doesn't allow managing the remote object. A CEL expression
+// failing to evaluate denies the object as well.
+func Check(p apisv1alpha1.Policy, obj *unstructured.Unstructured, limits celcheck.Limits) error {
+	if len(p.Allow) > 0 && matchingRule(p.Allow, obj) < 0 {
+		return fmt.Errorf("%w: %s doesn't match any allow rule", ErrDenied, describe(obj))
+	}
+	if i := matchingRule(p.Deny, obj); i >= 0 {
+		return fmt.Errorf("%w: %s matches deny rule %d", ErrDenied, describe(obj), i)
+	}
+	if p.CELExpression == "" {
+		return nil
+	}
+	allowed, err := celcheck.EvalWithVariables(p.CELExpression, obj.Object, CELVariables, limits)
+	if err != nil {
+		return fmt.Errorf("%w: cannot evaluate celExpression: %s", ErrDenied, err)
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s doesn't satisfy celExpression", ErrDenied, describe(obj))
+	}
+	return nil
+}
+
+// matchingRule returns the index of the first rule matching the object, or -1 if none does.
+func matchingRule(rules []apisv1alpha1.PolicyRule, obj *unstructured.Unstructured) int {
+	gvk := obj.GroupVersionKind()
+	for i, r := range rules {
+		if matches(r.Groups, gvk.Group) && matches(r.Kinds, gvk.Kind) && matches(r.Namespaces, obj.GetNamespace()) && matches(r.Names, obj.GetName()) {
+			return i
+		}
+	}
+	return -1
+}
+
+// matches tells whether the value matches any of the patterns, the empty list matching everything.
+// Malformed patterns don't match anything.
+func matches(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, value); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+func describe(obj *unstructured.Unstructured) string {
+	gk := obj.GroupVersionKind().GroupKind()
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", gk, obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", gk, obj.GetNamespace(), obj.GetName())
+}
@@ -0,0 +1,119 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+	"aerf.io/provider-k8s/internal/celcheck"
+)
+
+func TestCheck(t *testing.T) {
+	object := func(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		return obj
+	}
+	hostNetworkPod := object("v1", "Pod", "default", "pod")
+	hostNetworkPod.Object["spec"] = map[string]any{"hostNetwork": true}
+	teamPolicy := apisv1alpha1.Policy{
+		Allow: []apisv1alpha1.PolicyRule{
+			{Namespaces: []string{"team-*"}},
+			{Groups: []string{""}, Kinds: []string{"Namespace"}, Names: []string{"team-*"}},
+		},
+		Deny: []apisv1alpha1.PolicyRule{
+			{Groups: []string{"rbac.authorization.k8s.io"}, Kinds: []string{"ClusterRole*"}},
+			{Names: []string{"*-admin"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		policy  apisv1alpha1.Policy
+		obj     *unstructured.Unstructured
+		wantErr bool
+	}{
+		{
+			name: "empty policy",
+			obj:  object("rbac.authorization.k8s.io/v1", "ClusterRoleBinding", "", "cluster-admin"),
+		},
+		{
+			name:   "allowed namespace",
+			policy: teamPolicy,
+			obj:    object("apps/v1", "Deployment", "team-a", "app"),
+		},
+		{
+			name:   "allowed cluster-scoped object",
+			policy: teamPolicy,
+			obj:    object("v1", "Namespace", "", "team-b"),
+		},
+		{
+			name:    "not allowed namespace",
+			policy:  teamPolicy,
+			obj:     object("v1", "ConfigMap", "kube-system", "cm"),
+			wantErr: true,
+		},
+		{
+			name:    "not allowed cluster-scoped object",
+			policy:  teamPolicy,
+			obj:     object("rbac.authorization.k8s.io/v1", "ClusterRoleBinding", "", "binding"),
+			wantErr: true,
+		},
+		{
+			name:    "denied name",
+			policy:  teamPolicy,
+			obj:     object("rbac.authorization.k8s.io/v1", "RoleBinding", "team-a", "team-admin"),
+			wantErr: true,
+		},
+		{
+			name:    "denied kind",
+			policy:  apisv1alpha1.Policy{Deny: teamPolicy.Deny},
+			obj:     object("rbac.authorization.k8s.io/v1", "ClusterRoleBinding", "", "binding"),
+			wantErr: true,
+		},
+		{
+			name:   "CEL expression allows",
+			policy: apisv1alpha1.Policy{CELExpression: `self.metadata.name.startsWith("team-")`},
+			obj:    object("v1", "ConfigMap", "default", "team-cm"),
+		},
+		{
+			name:    "CEL expression denies",
+			policy:  apisv1alpha1.Policy{CELExpression: `self.metadata.name.startsWith("team-")`},
+			obj:     object("v1", "ConfigMap", "default", "cm"),
+			wantErr: true,
+		},
+		{
+			name:   "CEL expression reading a field missing from the manifest",
+			policy: apisv1alpha1.Policy{CELExpression: `!has(spec.hostNetwork) && !has(status.phase)`},
+			obj:    object("v1", "ConfigMap", "default", "cm"),
+		},
+		{
+			name:    "CEL expression reading a field of the manifest",
+			policy:  apisv1alpha1.Policy{CELExpression: `!has(spec.hostNetwork)`},
+			obj:     hostNetworkPod,
+			wantErr: true,
+		},
+		{
+			name:    "CEL expression fails",
+			policy:  apisv1alpha1.Policy{CELExpression: `spec.replicas < 3`},
+			obj:     object("v1", "ConfigMap", "default", "cm"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.policy, tt.obj, celcheck.Limits{})
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, errors.Is(err, ErrDenied), "expected ErrDenied, got %v", err)
+		})
+	}
+}
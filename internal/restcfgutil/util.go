@@ -6,6 +6,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -21,15 +22,35 @@ func RestConfigFromProviderConfig(ctx context.Context, pc *apisv1alpha1.Provider
 		return rc, errors.Wrap(err, "couldn't get rest.Config from in-cluster data")
 	}
 
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, cli, cd.CommonCredentialSelectors)
+	cfg, err := clientConfigFromProviderConfig(ctx, pc, cli)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get credentials")
+		return nil, err
 	}
-	cfg, err := clientcmd.NewClientConfigFromBytes(data)
+	rc, err := cfg.ClientConfig()
+	return rc, errors.Wrap(err, "failed to create *rest.Config from kubeconfig")
+}
+
+// NamespaceFromProviderConfig returns the namespace of the kubeconfig's current context, defaulting to "default".
+// It's always "default" for the injected identity.
+func NamespaceFromProviderConfig(ctx context.Context, pc *apisv1alpha1.ProviderConfig, cli client.Client) (string, error) {
+	if pc.Spec.Credentials.Source == xpv1.CredentialsSourceInjectedIdentity {
+		return metav1.NamespaceDefault, nil
+	}
+
+	cfg, err := clientConfigFromProviderConfig(ctx, pc, cli)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create clientConfig from raw bytes")
+		return "", err
 	}
+	ns, _, err := cfg.Namespace()
+	return ns, errors.Wrap(err, "failed to get namespace from kubeconfig")
+}
 
-	rc, err := cfg.ClientConfig()
-	return rc, errors.Wrap(err, "failed to create *rest.Config from kubeconfig")
+func clientConfigFromProviderConfig(ctx context.Context, pc *apisv1alpha1.ProviderConfig, cli client.Client) (clientcmd.ClientConfig, error) {
+	cd := pc.Spec.Credentials
+	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, cli, cd.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get credentials")
+	}
+	cfg, err := clientcmd.NewClientConfigFromBytes(data)
+	return cfg, errors.Wrap(err, "failed to create clientConfig from raw bytes")
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -123,3 +124,46 @@ users:
   user:
     token: kubeconfig-u-token
 `
+
+func TestNamespaceFromProviderConfig(t *testing.T) {
+	pc := &apisv1alpha1.ProviderConfig{
+		Spec: apisv1alpha1.ProviderConfigSpec{
+			Credentials: apisv1alpha1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Name: "name", Namespace: "ns"},
+						Key:             "key",
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		kubeconfig string
+		want       string
+	}{
+		{
+			name:       "context without namespace",
+			kubeconfig: kubeConfigFixture,
+			want:       "default",
+		},
+		{
+			name:       "context with namespace",
+			kubeconfig: strings.Replace(kubeConfigFixture, "    user: k8s\n", "    user: k8s\n    namespace: team\n", 1),
+			want:       "team",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &test.MockClient{MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+				obj.(*corev1.Secret).Data = map[string][]byte{"key": []byte(tt.kubeconfig)} //nolint:forcetypeassert // only Secrets are read
+				return nil
+			}}
+			ns, err := NamespaceFromProviderConfig(context.Background(), pc, cli)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, ns)
+		})
+	}
+}
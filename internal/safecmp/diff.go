@@ -1,10 +1,15 @@
 package safecmp
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/pmezard/go-difflib/difflib"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
 )
 
 // cmp.Diff is NOT safe for production code (it may panic), as documented in
@@ -19,6 +24,8 @@ func Diff(x, y any, opts ...cmp.Option) (out string) {
 	return cmp.Diff(x, y, opts...)
 }
 
+// DiffUnstructured describes how to get from x to y, as a RFC 6902 JSON Patch followed by a unified diff of their YAML
+// representations. Their status and managedFields are left out. It returns an empty string if there are no differences.
 func DiffUnstructured(x, y *unstructured.Unstructured) string {
 	xCopy := x.DeepCopy()
 	yCopy := y.DeepCopy()
@@ -26,7 +33,67 @@ func DiffUnstructured(x, y *unstructured.Unstructured) string {
 	cleanUnstructured(xCopy)
 	cleanUnstructured(yCopy)
 
-	return Diff(xCopy, yCopy)
+	xJSON, err := json.Marshal(xCopy.Object)
+	if err != nil {
+		return fmt.Sprintf("cannot marshal the observed object: %s", err)
+	}
+	yJSON, err := json.Marshal(yCopy.Object)
+	if err != nil {
+		return fmt.Sprintf("cannot marshal the desired object: %s", err)
+	}
+
+	// the objects are compared as decoded from JSON, so that e.g. int64 and float64 numbers are equal
+	var xDecoded, yDecoded any
+	if err := json.Unmarshal(xJSON, &xDecoded); err != nil {
+		return fmt.Sprintf("cannot unmarshal the observed object: %s", err)
+	}
+	if err := json.Unmarshal(yJSON, &yDecoded); err != nil {
+		return fmt.Sprintf("cannot unmarshal the desired object: %s", err)
+	}
+	patch := createPatch(xDecoded, yDecoded, "", nil)
+	if len(patch) == 0 {
+		return ""
+	}
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Sprintf("cannot marshal JSON patch: %s", err)
+	}
+
+	return fmt.Sprintf("JSON patch:\n%s\nYAML diff:\n%s", patchJSON, yamlDiff(xJSON, yJSON))
+}
+
+func yamlDiff(xJSON, yJSON []byte) string {
+	xYAML, err := yaml.JSONToYAML(xJSON)
+	if err != nil {
+		return fmt.Sprintf("cannot convert the observed object to YAML: %s", err)
+	}
+	yYAML, err := yaml.JSONToYAML(yJSON)
+	if err != nil {
+		return fmt.Sprintf("cannot convert the desired object to YAML: %s", err)
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(xYAML)),
+		B:        difflib.SplitLines(string(yYAML)),
+		FromFile: "observed",
+		ToFile:   "desired",
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Sprintf("cannot create YAML diff: %s", err)
+	}
+	return diff
+}
+
+// Truncate cuts s down to at most maxLen bytes, noting how many were cut off. Non-positive maxLen means no limit.
+func Truncate(s string, maxLen int) string {
+	if maxLen <= 0 || len(s) <= maxLen {
+		return s
+	}
+	cut := maxLen
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s\n... (%d bytes truncated)", strings.TrimRight(s[:cut], "\n"), len(s)-cut)
 }
 
 func cleanUnstructured(obj *unstructured.Unstructured) {
@@ -0,0 +1,83 @@
+package safecmp
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDiffUnstructured(t *testing.T) {
+	newCM := func(data map[string]any, status string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": "cm", "namespace": "default"},
+			"data":       data,
+			"status":     status,
+		}}
+	}
+
+	require.Empty(t, DiffUnstructured(newCM(map[string]any{"key": "value"}, "a"), newCM(map[string]any{"key": "value"}, "b")))
+
+	require.Equal(t, `JSON patch:
+[{"op":"add","path":"/data/added","value":"new"},{"op":"replace","path":"/data/key","value":"desired"}]
+YAML diff:
+--- observed
++++ desired
+@@ -1,6 +1,7 @@
+ apiVersion: v1
+ data:
+-  key: observed
++  added: new
++  key: desired
+ kind: ConfigMap
+ metadata:
+   name: cm
+`, DiffUnstructured(newCM(map[string]any{"key": "observed"}, "a"), newCM(map[string]any{"key": "desired", "added": "new"}, "b")))
+}
+
+func TestTruncate(t *testing.T) {
+	require.Equal(t, "short", Truncate("short", 10))
+	require.Equal(t, "unlimited", Truncate("unlimited", 0))
+	require.Equal(t, "abc\n... (3 bytes truncated)", Truncate("abcdef", 3))
+	// multi-byte runes are not split
+	require.Equal(t, "a\n... (5 bytes truncated)", Truncate("aźźx", 2))
+}
+
+func TestCreatePatchApplies(t *testing.T) {
+	list := func(n int) []any {
+		l := make([]any, 0, n)
+		for i := 0; i < n; i++ {
+			l = append(l, map[string]any{"name": fmt.Sprintf("item-%d", i)})
+		}
+		return l
+	}
+	x := map[string]any{"items": list(12), "a": "1", "b": map[string]any{"c": "2", "d": "3"}}
+	y := map[string]any{"items": list(2), "b": map[string]any{"c": "4", "e": "5"}, "f": []any{"6"}}
+
+	xJSON, err := json.Marshal(x)
+	require.NoError(t, err)
+	yJSON, err := json.Marshal(y)
+	require.NoError(t, err)
+	var xDecoded, yDecoded any
+	require.NoError(t, json.Unmarshal(xJSON, &xDecoded))
+	require.NoError(t, json.Unmarshal(yJSON, &yDecoded))
+
+	patch := createPatch(xDecoded, yDecoded, "", nil)
+	// the same objects always give the same patch
+	for i := 0; i < 10; i++ {
+		require.Equal(t, patch, createPatch(xDecoded, yDecoded, "", nil))
+	}
+
+	patchJSON, err := json.Marshal(patch)
+	require.NoError(t, err)
+	decoded, err := jsonpatch.DecodePatch(patchJSON)
+	require.NoError(t, err)
+	applied, err := decoded.Apply(xJSON)
+	require.NoError(t, err)
+	require.JSONEq(t, string(yJSON), string(applied))
+}
@@ -0,0 +1,68 @@
+package safecmp
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gomodules.xyz/jsonpatch/v2"
+)
+
+var pointerEncoder = strings.NewReplacer("~", "~0", "/", "~1")
+
+// createPatch appends the RFC 6902 JSON patch operations turning x into y, decoded from JSON, like
+// jsonpatch.CreatePatch does. The keys of objects are visited in order, so that the same objects always give the same
+// patch. The operations on arrays are emitted highest index first for removals, the order they depend on.
+func createPatch(x, y any, path string, patch []jsonpatch.Operation) []jsonpatch.Operation {
+	if x == nil && y == nil {
+		return patch
+	}
+	if reflect.TypeOf(x) != reflect.TypeOf(y) {
+		return append(patch, jsonpatch.NewOperation("replace", path, y))
+	}
+
+	switch xv := x.(type) {
+	case map[string]any:
+		yv := y.(map[string]any) //nolint:forcetypeassert // the types are checked above
+		for _, key := range sortedKeys(yv) {
+			p := path + "/" + pointerEncoder.Replace(key)
+			if _, ok := xv[key]; !ok {
+				patch = append(patch, jsonpatch.NewOperation("add", p, yv[key]))
+				continue
+			}
+			patch = createPatch(xv[key], yv[key], p, patch)
+		}
+		for _, key := range sortedKeys(xv) {
+			if _, ok := yv[key]; !ok {
+				patch = append(patch, jsonpatch.NewOperation("remove", path+"/"+pointerEncoder.Replace(key), nil))
+			}
+		}
+	case []any:
+		yv := y.([]any) //nolint:forcetypeassert // the types are checked above
+		n := min(len(xv), len(yv))
+		for i := len(xv) - 1; i >= n; i-- {
+			patch = append(patch, jsonpatch.NewOperation("remove", fmt.Sprintf("%s/%d", path, i), nil))
+		}
+		for i := n; i < len(yv); i++ {
+			patch = append(patch, jsonpatch.NewOperation("add", fmt.Sprintf("%s/%d", path, i), yv[i]))
+		}
+		for i := 0; i < n; i++ {
+			patch = createPatch(xv[i], yv[i], fmt.Sprintf("%s/%d", path, i), patch)
+		}
+	default:
+		if x != y {
+			patch = append(patch, jsonpatch.NewOperation("replace", path, y))
+		}
+	}
+	return patch
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
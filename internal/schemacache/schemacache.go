@@ -0,0 +1,111 @@
+// Package schemacache resolves the OpenAPI schemas of the kinds served by remote clusters and caches them.
+package schemacache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/cel/openapi/resolver"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Cache keeps the resolved schemas for a limited time, so that the changes of CustomResourceDefinitions are eventually
+// noticed. Kinds without a schema are cached as well, so that they aren't looked up on every call. It's safe for
+// concurrent use.
+type Cache struct {
+	ttl         time.Duration
+	now         func() time.Time
+	newResolver func(restCfg *rest.Config) (resolver.SchemaResolver, error)
+
+	mu      sync.Mutex
+	entries map[key]entry
+}
+
+type key struct {
+	host string
+	gvk  schema.GroupVersionKind
+}
+
+type entry struct {
+	// schema is nil if the kind has no schema.
+	schema  *spec.Schema
+	expires time.Time
+}
+
+// New returns a cache keeping the resolved schemas for ttl.
+func New(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl: ttl,
+		now: time.Now,
+		newResolver: func(restCfg *rest.Config) (resolver.SchemaResolver, error) {
+			dc, err := discovery.NewDiscoveryClientForConfig(restCfg)
+			if err != nil {
+				return nil, errors.Wrap(err, "cannot create discovery client")
+			}
+			return &resolver.ClientDiscoveryResolver{Discovery: dc}, nil
+		},
+		entries: map[key]entry{},
+	}
+}
+
+// For returns the resolver of the schemas served by the remote cluster at restCfg, backed by the cache.
+// The returned error wraps resolver.ErrSchemaNotFound if the kind has no schema.
+func (c *Cache) For(restCfg *rest.Config) resolver.SchemaResolver {
+	return &cachedResolver{cache: c, restCfg: restCfg}
+}
+
+type cachedResolver struct {
+	cache   *Cache
+	restCfg *rest.Config
+}
+
+func (r *cachedResolver) ResolveSchema(gvk schema.GroupVersionKind) (*spec.Schema, error) {
+	k := key{host: r.restCfg.Host, gvk: gvk}
+	if s, ok := r.cache.get(k); ok {
+		if s == nil {
+			return nil, errors.Wrapf(resolver.ErrSchemaNotFound, "cannot resolve group version kind %q", gvk)
+		}
+		return s, nil
+	}
+
+	res, err := r.cache.newResolver(r.restCfg)
+	if err != nil {
+		return nil, err
+	}
+	s, err := res.ResolveSchema(gvk)
+	switch {
+	case errors.Is(err, resolver.ErrSchemaNotFound):
+		r.cache.set(k, nil)
+		return nil, err
+	case err != nil:
+		return nil, errors.Wrapf(err, "cannot resolve the schema of %q", gvk)
+	}
+	r.cache.set(k, s)
+	return s, nil
+}
+
+func (c *Cache) get(k key) (*spec.Schema, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[k]
+	if !ok || c.now().After(e.expires) {
+		return nil, false
+	}
+	return e.schema, true
+}
+
+func (c *Cache) set(k key, s *spec.Schema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[k] = entry{schema: s, expires: now.Add(c.ttl)}
+}
@@ -0,0 +1,57 @@
+package schemacache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/cel/openapi/resolver"
+	"k8s.io/client-go/rest"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+type countingResolver struct {
+	schemas map[schema.GroupVersionKind]*spec.Schema
+	calls   int
+}
+
+func (r *countingResolver) ResolveSchema(gvk schema.GroupVersionKind) (*spec.Schema, error) {
+	r.calls++
+	if s, ok := r.schemas[gvk]; ok {
+		return s, nil
+	}
+	return nil, resolver.ErrSchemaNotFound
+}
+
+func TestCache(t *testing.T) {
+	deployGVK := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	unknownGVK := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Unknown"}
+	deploySchema := &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"object"}}}
+
+	res := &countingResolver{schemas: map[schema.GroupVersionKind]*spec.Schema{deployGVK: deploySchema}}
+	now := time.Now()
+	c := New(time.Minute)
+	c.now = func() time.Time { return now }
+	c.newResolver = func(*rest.Config) (resolver.SchemaResolver, error) { return res, nil }
+	r := c.For(&rest.Config{Host: "https://remote.example.com"})
+
+	for range 2 {
+		s, err := r.ResolveSchema(deployGVK)
+		require.NoError(t, err)
+		require.Same(t, deploySchema, s)
+
+		_, err = r.ResolveSchema(unknownGVK)
+		require.ErrorIs(t, err, resolver.ErrSchemaNotFound)
+	}
+	require.Equal(t, 2, res.calls, "schemas and their absence should be cached")
+
+	_, err := c.For(&rest.Config{Host: "https://other.example.com"}).ResolveSchema(deployGVK)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.calls, "schemas should be cached per remote cluster")
+
+	now = now.Add(2 * time.Minute)
+	_, err = r.ResolveSchema(deployGVK)
+	require.NoError(t, err)
+	require.Equal(t, 4, res.calls, "expired schemas should be resolved again")
+}
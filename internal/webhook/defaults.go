@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+	"aerf.io/provider-k8s/internal/restcfgutil"
+)
+
+const (
+	// LabelManagedBy is the standard label telling which tool manages the remote object.
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	// LabelObject holds the name of the Object managing the remote object.
+	LabelObject = objv1alpha1.Group + "/object"
+
+	managedBy = "provider-k8s"
+
+	// defaultTimeout bounds the calls to the remote cluster, so that an unresponsive one doesn't time the admission out.
+	defaultTimeout = 5 * time.Second
+)
+
+// ObjectDefaulter defaults the manifests of Objects on admission, so that the remote objects are addressed the same way
+// by every part of the provider. The namespace of the namespaced remote objects is set to the one of the kubeconfig's
+// current context, or "default", while the namespace of the cluster-scoped ones is removed. The scope of the remote
+// object is looked up on the remote cluster, the namespace is left as is if that fails.
+// The remote objects are labelled with the tool managing them and the name of their Object, unless the name is too
+// long for a label value.
+type ObjectDefaulter struct {
+	// Client reads the ProviderConfigs and their credentials. The namespace isn't defaulted if it's nil.
+	Client client.Client
+	Log    logging.Logger
+
+	// newRemoteClient builds the client of the remote cluster, defaults to client.New.
+	newRemoteClient func(rc *rest.Config) (client.Client, error)
+	// timeout bounds the calls to the remote cluster, defaults to defaultTimeout.
+	timeout time.Duration
+}
+
+var _ admission.CustomDefaulter = &ObjectDefaulter{}
+
+func (d *ObjectDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	o, ok := obj.(*objv1alpha1.Object)
+	if !ok {
+		return errors.Errorf("expected Object, got %T", obj)
+	}
+	if o.GetDeletionTimestamp() != nil {
+		return nil
+	}
+
+	content := map[string]any{}
+	if err := json.Unmarshal(o.Spec.ForProvider.Manifest.Raw, &content); err != nil {
+		// invalid manifests are rejected by the ObjectValidator
+		return nil //nolint:nilerr // see above
+	}
+	manifest := &unstructured.Unstructured{Object: content}
+	if manifest.GetAPIVersion() == "" || manifest.GetKind() == "" {
+		return nil
+	}
+
+	if d.Client != nil {
+		if err := d.defaultNamespace(ctx, o, manifest); err != nil {
+			d.log().Debug("Cannot default the namespace of the manifest", "object", o.GetName(), "error", err)
+		}
+	}
+
+	labels := manifest.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	if _, ok := labels[LabelManagedBy]; !ok {
+		labels[LabelManagedBy] = managedBy
+	}
+	if len(validation.IsValidLabelValue(o.GetName())) == 0 {
+		labels[LabelObject] = o.GetName()
+	}
+	manifest.SetLabels(labels)
+
+	raw, err := json.Marshal(manifest.Object)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal manifest")
+	}
+	o.Spec.ForProvider.Manifest = runtime.RawExtension{Raw: raw}
+	return nil
+}
+
+// defaultNamespace sets or removes the namespace of the manifest depending on the scope of its kind on the remote
+// cluster of the Object's ProviderConfig.
+func (d *ObjectDefaulter) defaultNamespace(ctx context.Context, o *objv1alpha1.Object, manifest *unstructured.Unstructured) error {
+	ref := o.GetProviderConfigReference()
+	if ref == nil {
+		return nil
+	}
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := d.Client.Get(ctx, types.NamespacedName{Name: ref.Name}, pc); err != nil {
+		return errors.Wrapf(err, "cannot get ProviderConfig %q", ref.Name)
+	}
+	rc, err := restcfgutil.RestConfigFromProviderConfig(ctx, pc, d.Client)
+	if err != nil {
+		return err
+	}
+	rc = rest.CopyConfig(rc)
+	rc.Timeout = d.timeout
+	if rc.Timeout <= 0 {
+		rc.Timeout = defaultTimeout
+	}
+	newClient := d.newRemoteClient
+	if newClient == nil {
+		newClient = func(rc *rest.Config) (client.Client, error) { return client.New(rc, client.Options{}) }
+	}
+	remoteCli, err := newClient(rc)
+	if err != nil {
+		return err
+	}
+	namespaced, err := remoteCli.IsObjectNamespaced(manifest)
+	if err != nil {
+		return errors.Wrapf(err, "cannot tell whether %s is namespaced", manifest.GroupVersionKind())
+	}
+
+	if !namespaced {
+		unstructured.RemoveNestedField(manifest.Object, "metadata", "namespace")
+		return nil
+	}
+	if manifest.GetNamespace() != "" {
+		return nil
+	}
+	ns, err := restcfgutil.NamespaceFromProviderConfig(ctx, pc, d.Client)
+	if err != nil {
+		return err
+	}
+	manifest.SetNamespace(ns)
+	return nil
+}
+
+func (d *ObjectDefaulter) log() logging.Logger {
+	if d.Log == nil {
+		return logging.NewNopLogger()
+	}
+	return d.Log
+}
@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+)
+
+func TestObjectDefaulter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, apisv1alpha1.SchemeBuilder.AddToScheme(scheme))
+
+	providerConfig := func(name, key string) *apisv1alpha1.ProviderConfig {
+		return &apisv1alpha1.ProviderConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: apisv1alpha1.ProviderConfigSpec{
+				Credentials: apisv1alpha1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceSecret,
+					CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+						SecretRef: &xpv1.SecretKeySelector{
+							SecretReference: xpv1.SecretReference{Name: "kubeconfig", Namespace: "crossplane-system"},
+							Key:             key,
+						},
+					},
+				},
+			},
+		}
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "crossplane-system"},
+		Data: map[string][]byte{
+			"kubeconfig":           []byte(kubeConfigFixture),
+			"kubeconfig-namespace": []byte(strings.Replace(kubeConfigFixture, "    user: remote\n", "    user: remote\n    namespace: team\n", 1)),
+		},
+	}
+	localCli := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(providerConfig("remote", "kubeconfig"), providerConfig("remote-namespace", "kubeconfig-namespace"), secret).
+		Build()
+
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}})
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	d := &ObjectDefaulter{
+		Client: localCli,
+		newRemoteClient: func(*rest.Config) (client.Client, error) {
+			return fake.NewClientBuilder().WithRESTMapper(mapper).Build(), nil
+		},
+	}
+
+	tests := []struct {
+		name           string
+		providerConfig string
+		manifest       string
+		want           string
+	}{
+		{
+			name:           "namespaced kind without namespace",
+			providerConfig: "remote",
+			manifest:       `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`,
+			want:           `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"labels":{"app.kubernetes.io/managed-by":"provider-k8s","k8s.aerf.io/object":"object"},"name":"cm","namespace":"default"}}`,
+		},
+		{
+			name:           "namespace of the kubeconfig's context",
+			providerConfig: "remote-namespace",
+			manifest:       `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`,
+			want:           `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"labels":{"app.kubernetes.io/managed-by":"provider-k8s","k8s.aerf.io/object":"object"},"name":"cm","namespace":"team"}}`,
+		},
+		{
+			name:           "namespaced kind with namespace",
+			providerConfig: "remote-namespace",
+			manifest:       `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"other","labels":{"app.kubernetes.io/managed-by":"helm"}}}`,
+			want:           `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"labels":{"app.kubernetes.io/managed-by":"helm","k8s.aerf.io/object":"object"},"name":"cm","namespace":"other"}}`,
+		},
+		{
+			name:           "cluster-scoped kind with namespace",
+			providerConfig: "remote",
+			manifest:       `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"ns","namespace":"default"}}`,
+			want:           `{"apiVersion":"v1","kind":"Namespace","metadata":{"labels":{"app.kubernetes.io/managed-by":"provider-k8s","k8s.aerf.io/object":"object"},"name":"ns"}}`,
+		},
+		{
+			name:           "unknown kind",
+			providerConfig: "remote",
+			manifest:       `{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"widget"}}`,
+			want:           `{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"labels":{"app.kubernetes.io/managed-by":"provider-k8s","k8s.aerf.io/object":"object"},"name":"widget"}}`,
+		},
+		{
+			name:           "missing ProviderConfig",
+			providerConfig: "missing",
+			manifest:       `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`,
+			want:           `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"labels":{"app.kubernetes.io/managed-by":"provider-k8s","k8s.aerf.io/object":"object"},"name":"cm"}}`,
+		},
+		{
+			name:           "invalid manifest",
+			providerConfig: "remote",
+			manifest:       `"cm"`,
+			want:           `"cm"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &objv1alpha1.Object{
+				ObjectMeta: metav1.ObjectMeta{Name: "object"},
+				Spec: objv1alpha1.ObjectSpec{
+					ForProvider: objv1alpha1.ObjectParameters{Manifest: runtime.RawExtension{Raw: []byte(tt.manifest)}},
+				},
+			}
+			o.SetProviderConfigReference(&xpv1.Reference{Name: tt.providerConfig})
+
+			require.NoError(t, d.Default(context.Background(), o))
+			require.JSONEq(t, tt.want, string(o.Spec.ForProvider.Manifest.Raw))
+		})
+	}
+}
+
+func TestObjectDefaulterUnresponsiveRemote(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, apisv1alpha1.SchemeBuilder.AddToScheme(scheme))
+
+	release := make(chan struct{})
+	unresponsive := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer unresponsive.Close()
+	defer close(release)
+
+	pc := &apisv1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "remote"},
+		Spec: apisv1alpha1.ProviderConfigSpec{
+			Credentials: apisv1alpha1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Name: "kubeconfig", Namespace: "crossplane-system"},
+						Key:             "kubeconfig",
+					},
+				},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "crossplane-system"},
+		Data:       map[string][]byte{"kubeconfig": []byte(strings.Replace(kubeConfigFixture, "https://remote.example.com", unresponsive.URL, 1))},
+	}
+	d := &ObjectDefaulter{
+		Client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(pc, secret).Build(),
+		timeout: 100 * time.Millisecond,
+	}
+	o := &objv1alpha1.Object{
+		ObjectMeta: metav1.ObjectMeta{Name: "object"},
+		Spec: objv1alpha1.ObjectSpec{
+			ForProvider: objv1alpha1.ObjectParameters{Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`)}},
+		},
+	}
+	o.SetProviderConfigReference(&xpv1.Reference{Name: pc.GetName()})
+
+	start := time.Now()
+	require.NoError(t, d.Default(context.Background(), o))
+	require.Less(t, time.Since(start), 5*time.Second)
+	require.JSONEq(t, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"labels":{"app.kubernetes.io/managed-by":"provider-k8s","k8s.aerf.io/object":"object"},"name":"cm"}}`, string(o.Spec.ForProvider.Manifest.Raw))
+}
@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+)
+
+const (
+	// dryRunFieldManager owns the fields of the dry-run applies, which are never persisted.
+	dryRunFieldManager   = "provider-k8s-webhook"
+	defaultDryRunTimeout = 5 * time.Second
+)
+
+// dryRun applies the desired object to the remote cluster of the Object's ProviderConfig in dry-run mode. The remote
+// API server's rejections of the object are returned as field errors of the manifest, while the errors preventing the
+// dry-run, e.g. an unreachable cluster, are returned as warnings. So are unknown kinds, as their
+// CustomResourceDefinitions may be applied alongside.
+func (v *ObjectValidator) dryRun(ctx context.Context, o *objv1alpha1.Object, pc *apisv1alpha1.ProviderConfig, rc *rest.Config, desired *unstructured.Unstructured) (admission.Warnings, error) {
+	timeout := defaultDryRunTimeout
+	if pc.Spec.Admission.DryRunTimeout != nil {
+		timeout = pc.Spec.Admission.DryRunTimeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	rc = rest.CopyConfig(rc)
+	rc.Timeout = timeout
+	newClient := v.newRemoteClient
+	if newClient == nil {
+		newClient = func(rc *rest.Config) (client.Client, error) { return client.New(rc, client.Options{}) }
+	}
+	remoteCli, err := newClient(rc)
+	if err != nil {
+		return skippedDryRun(err), nil
+	}
+
+	err = remoteCli.Patch(ctx, desired, client.Apply, client.DryRunAll, client.ForceOwnership, client.FieldOwner(dryRunFieldManager))
+	switch {
+	case err == nil:
+		return nil, nil
+	case apierrors.IsInvalid(err):
+		return nil, apierrors.NewInvalid(objectGroupKind, o.GetName(), remoteFieldErrors(err))
+	case apierrors.IsBadRequest(err):
+		return nil, apierrors.NewInvalid(objectGroupKind, o.GetName(), field.ErrorList{
+			field.Invalid(manifestPath, field.OmitValueType{}, "rejected by the remote cluster: "+err.Error()),
+		})
+	case meta.IsNoMatchError(err):
+		return admission.Warnings{fmt.Sprintf("Kind %s is not served by the remote cluster yet", desired.GroupVersionKind())}, nil
+	default:
+		return skippedDryRun(err), nil
+	}
+}
+
+// remoteFieldErrors returns the causes of the remote API server's Invalid error as errors of the manifest's fields.
+func remoteFieldErrors(err error) field.ErrorList {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil || len(status.Status().Details.Causes) == 0 {
+		return field.ErrorList{field.Invalid(manifestPath, field.OmitValueType{}, "rejected by the remote cluster: "+err.Error())}
+	}
+	causes := status.Status().Details.Causes
+	errs := make(field.ErrorList, 0, len(causes))
+	for _, cause := range causes {
+		fe := field.Invalid(manifestPath, field.OmitValueType{}, cause.Message)
+		if cause.Type != "" {
+			fe.Type = field.ErrorType(cause.Type)
+		}
+		if cause.Field != "" {
+			fe.Field += "." + cause.Field
+		}
+		errs = append(errs, fe)
+	}
+	return errs
+}
+
+func skippedDryRun(err error) admission.Warnings {
+	return admission.Warnings{"Skipped the dry-run apply against the remote cluster: " + err.Error()}
+}
@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+	"aerf.io/provider-k8s/internal/objectindex"
+)
+
+const kubeConfigFixture = `apiVersion: v1
+clusters:
+- cluster:
+    server: https://remote.example.com
+  name: remote
+contexts:
+- context:
+    cluster: remote
+    user: remote
+  name: remote
+current-context: remote
+kind: Config
+users:
+- name: remote
+  user:
+    token: token
+`
+
+func TestObjectValidatorDryRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, apisv1alpha1.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, objv1alpha1.SchemeBuilder.AddToScheme(scheme))
+
+	providerConfig := func(name string, dryRun bool) *apisv1alpha1.ProviderConfig {
+		return &apisv1alpha1.ProviderConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: apisv1alpha1.ProviderConfigSpec{
+				Credentials: apisv1alpha1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceSecret,
+					CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+						SecretRef: &xpv1.SecretKeySelector{
+							SecretReference: xpv1.SecretReference{Name: "kubeconfig", Namespace: "crossplane-system"},
+							Key:             "kubeconfig",
+						},
+					},
+				},
+				Admission: apisv1alpha1.Admission{DryRun: dryRun},
+			},
+		}
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "crossplane-system"},
+		Data:       map[string][]byte{"kubeconfig": []byte(kubeConfigFixture)},
+	}
+	localCli := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(providerConfig("dry-run", true), providerConfig("no-dry-run", false), secret).
+		WithIndex(&objv1alpha1.Object{}, objectindex.Field, objectindex.IndexObject).
+		WithIndex(&objv1alpha1.ObjectSet{}, objectindex.Field, objectindex.IndexObjectSet).
+		Build()
+
+	invalid := apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "deploy", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "replicas"), -1, "must be greater than or equal to 0"),
+		field.Required(field.NewPath("spec", "selector"), ""),
+	})
+
+	tests := []struct {
+		name           string
+		providerConfig string
+		remoteErr      error
+		wantApplied    bool
+		wantFields     []string
+		wantWarnings   bool
+	}{
+		{
+			name:           "accepted by the remote cluster",
+			providerConfig: "dry-run",
+			wantApplied:    true,
+		},
+		{
+			name:           "rejected by the remote cluster",
+			providerConfig: "dry-run",
+			remoteErr:      invalid,
+			wantApplied:    true,
+			wantFields:     []string{"spec.forProvider.manifest.spec.replicas", "spec.forProvider.manifest.spec.selector"},
+		},
+		{
+			name:           "bad request",
+			providerConfig: "dry-run",
+			remoteErr:      apierrors.NewBadRequest("json: cannot unmarshal string into Go struct field"),
+			wantApplied:    true,
+			wantFields:     []string{"spec.forProvider.manifest"},
+		},
+		{
+			name:           "unknown kind",
+			providerConfig: "dry-run",
+			remoteErr:      &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "example.com", Kind: "Widget"}},
+			wantApplied:    true,
+			wantWarnings:   true,
+		},
+		{
+			name:           "remote cluster unavailable",
+			providerConfig: "dry-run",
+			remoteErr:      apierrors.NewServiceUnavailable("unavailable"),
+			wantApplied:    true,
+			wantWarnings:   true,
+		},
+		{
+			name:           "ProviderConfig doesn't opt in",
+			providerConfig: "no-dry-run",
+		},
+		{
+			name:           "ProviderConfig doesn't exist",
+			providerConfig: "missing",
+			wantWarnings:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applied := false
+			v := &ObjectValidator{
+				Client: localCli,
+				newRemoteClient: func(rc *rest.Config) (client.Client, error) {
+					require.Equal(t, "https://remote.example.com", rc.Host)
+					return fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+						Patch: func(_ context.Context, _ client.WithWatch, _ client.Object, patch client.Patch, opts ...client.PatchOption) error {
+							applied = true
+							require.Equal(t, client.Apply, patch)
+							require.Equal(t, []string{metav1.DryRunAll}, (&client.PatchOptions{}).ApplyOptions(opts).DryRun)
+							return tt.remoteErr
+						},
+					}).Build(), nil
+				},
+			}
+			o := &objv1alpha1.Object{
+				ObjectMeta: metav1.ObjectMeta{Name: "object"},
+				Spec: objv1alpha1.ObjectSpec{
+					ForProvider: objv1alpha1.ObjectParameters{Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"deploy","namespace":"default"}}`)}},
+				},
+			}
+			o.SetProviderConfigReference(&xpv1.Reference{Name: tt.providerConfig})
+
+			warnings, err := v.ValidateCreate(context.Background(), o)
+			require.Equal(t, tt.wantApplied, applied)
+			require.Equal(t, tt.wantWarnings, len(warnings) > 0, "warnings: %v", warnings)
+
+			// the manifest isn't dry-run applied again if it didn't change, e.g. on finalizer updates
+			applied = false
+			_, updateErr := v.ValidateUpdate(context.Background(), o.DeepCopy(), o)
+			require.NoError(t, updateErr)
+			require.False(t, applied)
+
+			if len(tt.wantFields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, apierrors.IsInvalid(err), "expected Invalid error, got %v", err)
+			var fields []string
+			for _, cause := range err.(apierrors.APIStatus).Status().Details.Causes { //nolint:forcetypeassert // checked by IsInvalid
+				fields = append(fields, cause.Field)
+			}
+			require.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Package webhook implements the admission webhooks of the provider's APIs.
+//
+// The validators deny only the mistakes of the admitted object itself. The errors preventing a check, e.g. a missing
+// ProviderConfig or an unreachable remote cluster, are returned as warnings, so that they don't block the admission.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	"aerf.io/provider-k8s/internal/celcheck"
+)
+
+// ObjectValidator validates Objects on admission, so that mistakes in their manifests and readiness settings are
+// reported right away instead of on the first reconciliation.
+type ObjectValidator struct {
+	// Client reads the ProviderConfigs and their credentials, and lists the Objects by the objectindex.Field index,
+	// to check the manifests against the remote clusters. The checks are skipped if it's nil.
+	Client client.Client
+	// CELLimits bound the evaluation of the CEL expressions of the ProviderConfigs' policies.
+	CELLimits celcheck.Limits
+
+	// newRemoteClient builds the client of the remote cluster, defaults to client.New.
+	newRemoteClient func(rc *rest.Config) (client.Client, error)
+}
+
+var _ admission.CustomValidator = &ObjectValidator{}
+
+func (v *ObjectValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	o, ok := obj.(*objv1alpha1.Object)
+	if !ok {
+		return nil, errors.Errorf("expected Object, got %T", obj)
+	}
+	return v.validate(ctx, o, nil)
+}
+
+func (v *ObjectValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	o, ok := newObj.(*objv1alpha1.Object)
+	if !ok {
+		return nil, errors.Errorf("expected Object, got %T", newObj)
+	}
+	// Objects that are being deleted must stay updatable, so that their finalizers can be removed.
+	if o.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+	old, ok := oldObj.(*objv1alpha1.Object)
+	if !ok {
+		return nil, errors.Errorf("expected Object, got %T", oldObj)
+	}
+	return v.validate(ctx, o, old)
+}
+
+func (v *ObjectValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+var (
+	objectGroupKind = objv1alpha1.SchemeGroupVersion.WithKind(objv1alpha1.ObjectKind).GroupKind()
+	manifestPath    = field.NewPath("spec", "forProvider", "manifest")
+)
+
+func (v *ObjectValidator) validate(ctx context.Context, o, old *objv1alpha1.Object) (admission.Warnings, error) {
+	desired, errs := validateManifest(manifestPath, o.Spec.ForProvider.Manifest.Raw)
+	errs = append(errs, validateReadiness(field.NewPath("spec", "readiness"), o.Spec.Readiness, desired)...)
+	if len(errs) > 0 {
+		return nil, apierrors.NewInvalid(objectGroupKind, o.GetName(), errs)
+	}
+	if v.Client == nil {
+		return nil, nil
+	}
+	return v.validateRemote(ctx, o, old, desired)
+}
+
+// validateManifest checks that the manifest decodes into an object with apiVersion, kind and name.
+// The decoded object is returned only if it's valid.
+func validateManifest(path *field.Path, raw []byte) (*unstructured.Unstructured, field.ErrorList) {
+	content := map[string]any{}
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return nil, field.ErrorList{field.Invalid(path, field.OmitValueType{}, "must be a JSON object: "+err.Error())}
+	}
+
+	manifest := &unstructured.Unstructured{Object: content}
+	var errs field.ErrorList
+	if manifest.GetAPIVersion() == "" {
+		errs = append(errs, field.Required(path.Child("apiVersion"), ""))
+	} else if _, err := schema.ParseGroupVersion(manifest.GetAPIVersion()); err != nil {
+		errs = append(errs, field.Invalid(path.Child("apiVersion"), manifest.GetAPIVersion(), err.Error()))
+	}
+	if manifest.GetKind() == "" {
+		errs = append(errs, field.Required(path.Child("kind"), ""))
+	}
+	if manifest.GetName() == "" {
+		errs = append(errs, field.Required(path.Child("metadata", "name"), ""))
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	desired := &unstructured.Unstructured{}
+	if err := json.Unmarshal(raw, desired); err != nil {
+		return nil, field.ErrorList{field.Invalid(path, field.OmitValueType{}, err.Error())}
+	}
+	return desired, nil
+}
+
+var readinessPolicies = []string{
+	string(objv1alpha1.ReadinessPolicySuccessfulCreate),
+	string(objv1alpha1.ReadinessPolicyDeriveFromObject),
+	string(objv1alpha1.ReadinessPolicyUseCELExpression),
+	string(objv1alpha1.ReadinessPolicyMatchConditions),
+	string(objv1alpha1.ReadinessPolicyAuto),
+}
+
+// celVariables are the top-level keys of remote objects declared as CEL variables, in addition to the manifest's own.
+var celVariables = []string{"apiVersion", "kind", "metadata", "spec", "status"}
+
+// validateReadiness checks that only the settings of the readiness policy are set and that the CEL expression compiles.
+// The expression is compiled with the desired object's top-level keys declared as variables, if it's given.
+func validateReadiness(path *field.Path, readiness objv1alpha1.Readiness, desired *unstructured.Unstructured) field.ErrorList {
+	var errs field.ErrorList
+	switch readiness.Policy {
+	case "", objv1alpha1.ReadinessPolicySuccessfulCreate, objv1alpha1.ReadinessPolicyDeriveFromObject, objv1alpha1.ReadinessPolicyAuto:
+	case objv1alpha1.ReadinessPolicyUseCELExpression:
+		if readiness.CELExpression == "" {
+			errs = append(errs, field.Required(path.Child("celExpression"), "must be set if policy is "+string(readiness.Policy)))
+		}
+	case objv1alpha1.ReadinessPolicyMatchConditions:
+		if len(readiness.Conditions) == 0 {
+			errs = append(errs, field.Required(path.Child("conditions"), "must be set if policy is "+string(readiness.Policy)))
+		}
+	default:
+		return append(errs, field.NotSupported(path.Child("policy"), readiness.Policy, readinessPolicies))
+	}
+
+	if readiness.Policy != objv1alpha1.ReadinessPolicyUseCELExpression {
+		if readiness.CELExpression != "" {
+			errs = append(errs, field.Forbidden(path.Child("celExpression"), "may only be set if policy is "+string(objv1alpha1.ReadinessPolicyUseCELExpression)))
+		}
+		if readiness.CELCostLimit != nil {
+			errs = append(errs, field.Forbidden(path.Child("celCostLimit"), "may only be set if policy is "+string(objv1alpha1.ReadinessPolicyUseCELExpression)))
+		}
+	}
+	if readiness.Policy != objv1alpha1.ReadinessPolicyMatchConditions {
+		if len(readiness.Conditions) > 0 {
+			errs = append(errs, field.Forbidden(path.Child("conditions"), "may only be set if policy is "+string(objv1alpha1.ReadinessPolicyMatchConditions)))
+		}
+		if readiness.ConditionsMatch != "" {
+			errs = append(errs, field.Forbidden(path.Child("conditionsMatch"), "may only be set if policy is "+string(objv1alpha1.ReadinessPolicyMatchConditions)))
+		}
+		if readiness.CheckObservedGeneration {
+			errs = append(errs, field.Forbidden(path.Child("checkObservedGeneration"), "may only be set if policy is "+string(objv1alpha1.ReadinessPolicyMatchConditions)))
+		}
+	}
+
+	if readiness.Policy == objv1alpha1.ReadinessPolicyUseCELExpression && readiness.CELExpression != "" {
+		vars := slices.Clone(celVariables)
+		if desired != nil {
+			for k := range desired.Object {
+				vars = append(vars, k)
+			}
+		}
+		if err := celcheck.Compile(readiness.CELExpression, vars); err != nil {
+			errs = append(errs, field.Invalid(path.Child("celExpression"), readiness.CELExpression, err.Error()))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+)
+
+func TestObjectValidator(t *testing.T) {
+	const cm = `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"default"},"data":{"key":"value"}}`
+
+	tests := []struct {
+		name       string
+		manifest   string
+		readiness  objv1alpha1.Readiness
+		wantFields []string
+	}{
+		{
+			name:     "valid",
+			manifest: cm,
+		},
+		{
+			name:     "valid CEL expression using the manifest's keys",
+			manifest: cm,
+			readiness: objv1alpha1.Readiness{
+				Policy:        objv1alpha1.ReadinessPolicyUseCELExpression,
+				CELExpression: `has(data.key) && self.metadata.name == "cm"`,
+				CELCostLimit:  ptr.To[int64](100),
+			},
+		},
+		{
+			name:       "manifest isn't an object",
+			manifest:   `"cm"`,
+			wantFields: []string{"spec.forProvider.manifest"},
+		},
+		{
+			name:       "manifest without apiVersion, kind and name",
+			manifest:   `{"metadata":{"namespace":"default"}}`,
+			wantFields: []string{"spec.forProvider.manifest.apiVersion", "spec.forProvider.manifest.kind", "spec.forProvider.manifest.metadata.name"},
+		},
+		{
+			name:       "manifest with invalid apiVersion",
+			manifest:   `{"apiVersion":"apps/v1/beta","kind":"Deployment","metadata":{"name":"deploy"}}`,
+			wantFields: []string{"spec.forProvider.manifest.apiVersion"},
+		},
+		{
+			name:     "CEL expression doesn't compile",
+			manifest: cm,
+			readiness: objv1alpha1.Readiness{
+				Policy:        objv1alpha1.ReadinessPolicyUseCELExpression,
+				CELExpression: `status.ready ==`,
+			},
+			wantFields: []string{"spec.readiness.celExpression"},
+		},
+		{
+			name:     "CEL expression references an undeclared variable",
+			manifest: cm,
+			readiness: objv1alpha1.Readiness{
+				Policy:        objv1alpha1.ReadinessPolicyUseCELExpression,
+				CELExpression: `stauts.ready`,
+			},
+			wantFields: []string{"spec.readiness.celExpression"},
+		},
+		{
+			name:       "CEL expression is missing",
+			manifest:   cm,
+			readiness:  objv1alpha1.Readiness{Policy: objv1alpha1.ReadinessPolicyUseCELExpression},
+			wantFields: []string{"spec.readiness.celExpression"},
+		},
+		{
+			name:     "settings of other policies",
+			manifest: cm,
+			readiness: objv1alpha1.Readiness{
+				Policy:                  objv1alpha1.ReadinessPolicyDeriveFromObject,
+				CELExpression:           `true`,
+				CELCostLimit:            ptr.To[int64](100),
+				Conditions:              []objv1alpha1.ReadinessCondition{{Type: "Ready"}},
+				ConditionsMatch:         objv1alpha1.ConditionsMatchAny,
+				CheckObservedGeneration: true,
+			},
+			wantFields: []string{
+				"spec.readiness.celExpression",
+				"spec.readiness.celCostLimit",
+				"spec.readiness.conditions",
+				"spec.readiness.conditionsMatch",
+				"spec.readiness.checkObservedGeneration",
+			},
+		},
+		{
+			name:       "conditions are missing",
+			manifest:   cm,
+			readiness:  objv1alpha1.Readiness{Policy: objv1alpha1.ReadinessPolicyMatchConditions},
+			wantFields: []string{"spec.readiness.conditions"},
+		},
+		{
+			name:       "unknown policy",
+			manifest:   cm,
+			readiness:  objv1alpha1.Readiness{Policy: "Eventually"},
+			wantFields: []string{"spec.readiness.policy"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &objv1alpha1.Object{
+				ObjectMeta: metav1.ObjectMeta{Name: "object"},
+				Spec: objv1alpha1.ObjectSpec{
+					ForProvider: objv1alpha1.ObjectParameters{Manifest: runtime.RawExtension{Raw: []byte(tt.manifest)}},
+					Readiness:   tt.readiness,
+				},
+			}
+
+			_, err := (&ObjectValidator{}).ValidateCreate(context.Background(), o)
+			if len(tt.wantFields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, apierrors.IsInvalid(err), "expected Invalid error, got %v", err)
+			var fields []string
+			for _, cause := range err.(apierrors.APIStatus).Status().Details.Causes { //nolint:forcetypeassert // checked by IsInvalid
+				fields = append(fields, cause.Field)
+			}
+			require.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
+
+func TestObjectValidatorAllowsDeletion(t *testing.T) {
+	o := &objv1alpha1.Object{
+		ObjectMeta: metav1.ObjectMeta{Name: "object", DeletionTimestamp: ptr.To(metav1.Now())},
+		Spec: objv1alpha1.ObjectSpec{
+			ForProvider: objv1alpha1.ObjectParameters{Manifest: runtime.RawExtension{Raw: []byte(`{}`)}},
+		},
+	}
+
+	_, err := (&ObjectValidator{}).ValidateUpdate(context.Background(), o, o)
+	require.NoError(t, err)
+	_, err = (&ObjectValidator{}).ValidateCreate(context.Background(), o)
+	require.Error(t, err)
+}
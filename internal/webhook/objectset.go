@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+	"aerf.io/provider-k8s/internal/celcheck"
+	"aerf.io/provider-k8s/internal/policy"
+)
+
+// ObjectSetValidator validates ObjectSets on admission, so that malformed manifests and the remote objects denied by
+// the policy of the ObjectSet's ProviderConfig are reported right away instead of on the first reconciliation.
+type ObjectSetValidator struct {
+	// Client reads the ProviderConfigs. The policies aren't checked if it's nil.
+	Client client.Client
+	// CELLimits bound the evaluation of the CEL expressions of the ProviderConfigs' policies.
+	CELLimits celcheck.Limits
+}
+
+var _ admission.CustomValidator = &ObjectSetValidator{}
+
+func (v *ObjectSetValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	s, ok := obj.(*objv1alpha1.ObjectSet)
+	if !ok {
+		return nil, errors.Errorf("expected ObjectSet, got %T", obj)
+	}
+	return v.validate(ctx, s, nil)
+}
+
+func (v *ObjectSetValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	s, ok := newObj.(*objv1alpha1.ObjectSet)
+	if !ok {
+		return nil, errors.Errorf("expected ObjectSet, got %T", newObj)
+	}
+	// ObjectSets that are being deleted must stay updatable, so that their finalizers can be removed.
+	if s.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+	old, ok := oldObj.(*objv1alpha1.ObjectSet)
+	if !ok {
+		return nil, errors.Errorf("expected ObjectSet, got %T", oldObj)
+	}
+	return v.validate(ctx, s, old)
+}
+
+func (v *ObjectSetValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+var (
+	objectSetGroupKind = objv1alpha1.SchemeGroupVersion.WithKind(objv1alpha1.ObjectSetKind).GroupKind()
+	manifestsPath      = field.NewPath("spec", "forProvider", "manifests")
+)
+
+// validate checks that every manifest decodes into an object and is allowed by the ProviderConfig's policy. Given the
+// old ObjectSet, the policy is checked only if the manifests or the ProviderConfig changed, so that the ObjectSets
+// denied by a newer policy stay updatable.
+func (v *ObjectSetValidator) validate(ctx context.Context, s, old *objv1alpha1.ObjectSet) (admission.Warnings, error) {
+	var errs field.ErrorList
+	desired := make([]*unstructured.Unstructured, 0, len(s.Spec.ForProvider.Manifests))
+	for i, m := range s.Spec.ForProvider.Manifests {
+		d, manifestErrs := validateManifest(manifestsPath.Index(i).Child("manifest"), m.Manifest.Raw)
+		errs = append(errs, manifestErrs...)
+		desired = append(desired, d)
+	}
+	if len(errs) > 0 {
+		return nil, apierrors.NewInvalid(objectSetGroupKind, s.GetName(), errs)
+	}
+
+	ref := s.GetProviderConfigReference()
+	if v.Client == nil || ref == nil || sameManifests(old, s) {
+		return nil, nil
+	}
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := v.Client.Get(ctx, types.NamespacedName{Name: ref.Name}, pc); err != nil {
+		return skippedRemoteChecks(errors.Wrapf(err, "cannot get ProviderConfig %q", ref.Name)), nil
+	}
+	for i, d := range desired {
+		if err := policy.Check(pc.Spec.Policy, d, v.CELLimits); err != nil {
+			errs = append(errs, field.Forbidden(manifestsPath.Index(i).Child("manifest"), err.Error()))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, apierrors.NewInvalid(objectSetGroupKind, s.GetName(), errs)
+	}
+	return nil, nil
+}
+
+// sameManifests tells whether the old ObjectSet has the same manifests and ProviderConfig as the new one.
+func sameManifests(old, s *objv1alpha1.ObjectSet) bool {
+	if old == nil {
+		return false
+	}
+	oldRef, ref := old.GetProviderConfigReference(), s.GetProviderConfigReference()
+	if oldRef == nil || ref == nil || oldRef.Name != ref.Name {
+		return false
+	}
+	return equality.Semantic.DeepEqual(old.Spec.ForProvider.Manifests, s.Spec.ForProvider.Manifests)
+}
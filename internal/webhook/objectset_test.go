@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+)
+
+func TestObjectSetValidator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, apisv1alpha1.SchemeBuilder.AddToScheme(scheme))
+
+	providerConfig := &apisv1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "remote"},
+		Spec: apisv1alpha1.ProviderConfigSpec{
+			Policy: apisv1alpha1.Policy{
+				Deny: []apisv1alpha1.PolicyRule{{Namespaces: []string{"kube-system"}}, {Kinds: []string{"ClusterRoleBinding"}}},
+			},
+		},
+	}
+	v := &ObjectSetValidator{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(providerConfig).Build()}
+
+	objectSet := func(providerConfig string, manifests ...string) *objv1alpha1.ObjectSet {
+		s := &objv1alpha1.ObjectSet{ObjectMeta: metav1.ObjectMeta{Name: "set"}}
+		for _, m := range manifests {
+			s.Spec.ForProvider.Manifests = append(s.Spec.ForProvider.Manifests, objv1alpha1.ObjectSetManifest{Manifest: runtime.RawExtension{Raw: []byte(m)}})
+		}
+		s.SetProviderConfigReference(&xpv1.Reference{Name: providerConfig})
+		return s
+	}
+	const (
+		allowed = `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"default"}}`
+		denied  = `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"kube-system"}}`
+		binding = `{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRoleBinding","metadata":{"name":"admin"}}`
+	)
+
+	tests := []struct {
+		name         string
+		old          *objv1alpha1.ObjectSet
+		s            *objv1alpha1.ObjectSet
+		wantFields   []string
+		wantWarnings bool
+	}{
+		{
+			name: "allowed manifests",
+			s:    objectSet("remote", allowed, allowed),
+		},
+		{
+			name:       "denied manifests",
+			s:          objectSet("remote", allowed, denied, binding),
+			wantFields: []string{"spec.forProvider.manifests[1].manifest", "spec.forProvider.manifests[2].manifest"},
+		},
+		{
+			name:       "malformed manifest",
+			s:          objectSet("remote", allowed, `{"apiVersion":"v1","kind":"ConfigMap"}`),
+			wantFields: []string{"spec.forProvider.manifests[1].manifest.metadata.name"},
+		},
+		{
+			name:       "update adding a denied manifest",
+			old:        objectSet("remote", allowed),
+			s:          objectSet("remote", allowed, denied),
+			wantFields: []string{"spec.forProvider.manifests[1].manifest"},
+		},
+		{
+			name: "update of an ObjectSet denied by a newer policy",
+			old:  objectSet("remote", denied),
+			s:    objectSet("remote", denied),
+		},
+		{
+			name:         "missing ProviderConfig",
+			s:            objectSet("missing", denied),
+			wantWarnings: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				warnings []string
+				err      error
+			)
+			if tt.old == nil {
+				warnings, err = v.ValidateCreate(context.Background(), tt.s)
+			} else {
+				warnings, err = v.ValidateUpdate(context.Background(), tt.old, tt.s)
+			}
+			require.Equal(t, tt.wantWarnings, len(warnings) > 0, "warnings: %v", warnings)
+			if len(tt.wantFields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, apierrors.IsInvalid(err), "expected Invalid error, got %v", err)
+			var fields []string
+			for _, cause := range err.(apierrors.APIStatus).Status().Details.Causes { //nolint:forcetypeassert // checked by IsInvalid
+				fields = append(fields, cause.Field)
+			}
+			require.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package webhook
+
+import (
+	"context"
+	"path"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+	"aerf.io/provider-k8s/internal/celcheck"
+	"aerf.io/provider-k8s/internal/policy"
+	"aerf.io/provider-k8s/internal/restcfgutil"
+)
+
+// ProviderConfigValidator validates ProviderConfigs on admission, so that wrong credentials are reported right away
+// instead of by every Object using them.
+type ProviderConfigValidator struct {
+	// Client reads the Secrets holding the credentials. The Secrets aren't checked if it's nil.
+	Client client.Client
+	// Probe enables calling the /version endpoint of the remote cluster, which is reported with a warning if it can't
+	// be reached.
+	Probe bool
+}
+
+var _ admission.CustomValidator = &ProviderConfigValidator{}
+
+func (v *ProviderConfigValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pc, ok := obj.(*apisv1alpha1.ProviderConfig)
+	if !ok {
+		return nil, errors.Errorf("expected ProviderConfig, got %T", obj)
+	}
+	return v.validate(ctx, pc)
+}
+
+func (v *ProviderConfigValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	pc, ok := newObj.(*apisv1alpha1.ProviderConfig)
+	if !ok {
+		return nil, errors.Errorf("expected ProviderConfig, got %T", newObj)
+	}
+	old, ok := oldObj.(*apisv1alpha1.ProviderConfig)
+	if !ok {
+		return nil, errors.Errorf("expected ProviderConfig, got %T", oldObj)
+	}
+	// ProviderConfigs must stay updatable by their controllers, e.g. to remove finalizers, even if their credentials
+	// are gone, so only the changes of the spec are validated.
+	if pc.GetDeletionTimestamp() != nil || equality.Semantic.DeepEqual(old.Spec, pc.Spec) {
+		return nil, nil
+	}
+	return v.validate(ctx, pc)
+}
+
+func (v *ProviderConfigValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+var (
+	providerConfigGroupKind = apisv1alpha1.SchemeGroupVersion.WithKind(apisv1alpha1.ProviderConfigKind).GroupKind()
+
+	credentialsSources = []string{
+		string(xpv1.CredentialsSourceSecret),
+		string(xpv1.CredentialsSourceInjectedIdentity),
+		string(xpv1.CredentialsSourceEnvironment),
+		string(xpv1.CredentialsSourceFilesystem),
+	}
+)
+
+const probeTimeout = 5 * time.Second
+
+func (v *ProviderConfigValidator) validate(ctx context.Context, pc *apisv1alpha1.ProviderConfig) (admission.Warnings, error) {
+	path := field.NewPath("spec", "credentials")
+	cd := pc.Spec.Credentials
+
+	var (
+		errs     field.ErrorList
+		warnings admission.Warnings
+	)
+	switch cd.Source {
+	case xpv1.CredentialsSourceSecret:
+		if cd.SecretRef == nil {
+			errs = append(errs, field.Required(path.Child("secretRef"), "must be set if source is "+string(cd.Source)))
+		} else if v.Client != nil {
+			w, secretErrs := v.validateSecretRef(ctx, path.Child("secretRef"), cd.SecretRef)
+			warnings = append(warnings, w...)
+			errs = append(errs, secretErrs...)
+		}
+	case xpv1.CredentialsSourceEnvironment:
+		if cd.Env == nil {
+			errs = append(errs, field.Required(path.Child("env"), "must be set if source is "+string(cd.Source)))
+		}
+	case xpv1.CredentialsSourceFilesystem:
+		if cd.Fs == nil {
+			errs = append(errs, field.Required(path.Child("fs"), "must be set if source is "+string(cd.Source)))
+		}
+	case xpv1.CredentialsSourceInjectedIdentity:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("source"), cd.Source, credentialsSources))
+	}
+
+	errs = append(errs, validatePolicy(field.NewPath("spec", "policy"), pc.Spec.Policy)...)
+	if d := pc.Spec.Admission.DryRunTimeout; d != nil && d.Duration <= 0 {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "admission", "dryRunTimeout"), d.Duration.String(), "must be positive"))
+	}
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(providerConfigGroupKind, pc.GetName(), errs)
+	}
+
+	if v.Probe && v.Client != nil {
+		if err := v.probe(ctx, pc); err != nil {
+			warnings = append(warnings, "Cannot reach the remote cluster: "+err.Error())
+		}
+	}
+	return warnings, nil
+}
+
+// validatePolicy checks that the patterns of the policy's rules are well-formed and that its CEL expression compiles.
+func validatePolicy(fldPath *field.Path, p apisv1alpha1.Policy) field.ErrorList {
+	var errs field.ErrorList
+	for _, list := range []struct {
+		name  string
+		rules []apisv1alpha1.PolicyRule
+	}{{"allow", p.Allow}, {"deny", p.Deny}} {
+		for i, r := range list.rules {
+			rulePath := fldPath.Child(list.name).Index(i)
+			errs = append(errs, validatePatterns(rulePath.Child("groups"), r.Groups)...)
+			errs = append(errs, validatePatterns(rulePath.Child("kinds"), r.Kinds)...)
+			errs = append(errs, validatePatterns(rulePath.Child("namespaces"), r.Namespaces)...)
+			errs = append(errs, validatePatterns(rulePath.Child("names"), r.Names)...)
+		}
+	}
+	if p.CELExpression != "" {
+		if err := celcheck.Compile(p.CELExpression, policy.CELVariables); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("celExpression"), p.CELExpression, err.Error()))
+		}
+	}
+	return errs
+}
+
+func validatePatterns(fldPath *field.Path, patterns []string) field.ErrorList {
+	var errs field.ErrorList
+	for i, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Index(i), pattern, err.Error()))
+		}
+	}
+	return errs
+}
+
+// validateSecretRef checks that the referenced Secret key holds a kubeconfig. A missing Secret is an error, while the
+// other errors reading it are returned as warnings.
+func (v *ProviderConfigValidator) validateSecretRef(ctx context.Context, path *field.Path, ref *xpv1.SecretKeySelector) (admission.Warnings, field.ErrorList) {
+	secret := &corev1.Secret{}
+	err := v.Client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil, field.ErrorList{field.NotFound(path, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}.String())}
+	case err != nil:
+		return admission.Warnings{"Skipped the check of the credentials Secret: " + err.Error()}, nil
+	}
+
+	data, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, field.ErrorList{field.NotFound(path.Child("key"), ref.Key)}
+	}
+	cfg, err := clientcmd.NewClientConfigFromBytes(data)
+	if err == nil {
+		_, err = cfg.ClientConfig()
+	}
+	if err != nil {
+		return nil, field.ErrorList{field.Invalid(path.Child("key"), ref.Key, "must hold a valid kubeconfig: "+err.Error())}
+	}
+	return nil, nil
+}
+
+// probe calls the /version endpoint of the remote cluster of the ProviderConfig.
+func (v *ProviderConfigValidator) probe(ctx context.Context, pc *apisv1alpha1.ProviderConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	rc, err := restcfgutil.RestConfigFromProviderConfig(ctx, pc, v.Client)
+	if err != nil {
+		return err
+	}
+	rc = rest.CopyConfig(rc)
+	rc.Timeout = probeTimeout
+	dc, err := discovery.NewDiscoveryClientForConfig(rc)
+	if err != nil {
+		return errors.Wrap(err, "cannot create discovery client")
+	}
+	_, err = dc.ServerVersion()
+	return errors.Wrap(err, "cannot get server version")
+}
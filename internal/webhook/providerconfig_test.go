@@ -0,0 +1,166 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+)
+
+func TestProviderConfigValidator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, apisv1alpha1.SchemeBuilder.AddToScheme(scheme))
+
+	reachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"major":"1","minor":"29","gitVersion":"v1.29.3"}`))
+	}))
+	defer reachable.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	kubeconfig := func(server string) []byte {
+		return []byte(fmt.Sprintf(`apiVersion: v1
+clusters:
+- cluster:
+    server: %s
+  name: remote
+contexts:
+- context:
+    cluster: remote
+    user: remote
+  name: remote
+current-context: remote
+kind: Config
+users:
+- name: remote
+  user:
+    token: token
+`, server))
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "crossplane-system"},
+		Data: map[string][]byte{
+			"reachable":   kubeconfig(reachable.URL),
+			"unreachable": kubeconfig(unreachable.URL),
+			"malformed":   []byte("clusters: ["),
+			"empty":       []byte("apiVersion: v1\nkind: Config\n"),
+		},
+	}
+	localCli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	secretRef := func(name, key string) *xpv1.SecretKeySelector {
+		return &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: name, Namespace: "crossplane-system"}, Key: key}
+	}
+
+	tests := []struct {
+		name         string
+		credentials  apisv1alpha1.ProviderCredentials
+		admission    apisv1alpha1.Admission
+		policy       apisv1alpha1.Policy
+		wantFields   []string
+		wantWarnings bool
+	}{
+		{
+			name:        "reachable cluster",
+			credentials: apisv1alpha1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret, CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: secretRef("kubeconfig", "reachable")}},
+		},
+		{
+			name:         "unreachable cluster",
+			credentials:  apisv1alpha1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret, CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: secretRef("kubeconfig", "unreachable")}},
+			wantWarnings: true,
+		},
+		{
+			name:        "unsupported source",
+			credentials: apisv1alpha1.ProviderCredentials{Source: xpv1.CredentialsSourceNone},
+			wantFields:  []string{"spec.credentials.source"},
+		},
+		{
+			name:        "missing secretRef",
+			credentials: apisv1alpha1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret},
+			wantFields:  []string{"spec.credentials.secretRef"},
+		},
+		{
+			name:        "missing Secret",
+			credentials: apisv1alpha1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret, CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: secretRef("other", "reachable")}},
+			wantFields:  []string{"spec.credentials.secretRef"},
+		},
+		{
+			name:        "missing key",
+			credentials: apisv1alpha1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret, CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: secretRef("kubeconfig", "other")}},
+			wantFields:  []string{"spec.credentials.secretRef.key"},
+		},
+		{
+			name:        "malformed kubeconfig",
+			credentials: apisv1alpha1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret, CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: secretRef("kubeconfig", "malformed")}},
+			wantFields:  []string{"spec.credentials.secretRef.key"},
+		},
+		{
+			name:        "kubeconfig without cluster",
+			credentials: apisv1alpha1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret, CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: secretRef("kubeconfig", "empty")}},
+			wantFields:  []string{"spec.credentials.secretRef.key"},
+		},
+		{
+			name:        "missing env",
+			credentials: apisv1alpha1.ProviderCredentials{Source: xpv1.CredentialsSourceEnvironment},
+			wantFields:  []string{"spec.credentials.env"},
+		},
+		{
+			name:        "invalid policy",
+			credentials: apisv1alpha1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret, CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: secretRef("kubeconfig", "reachable")}},
+			policy: apisv1alpha1.Policy{
+				Allow:         []apisv1alpha1.PolicyRule{{Namespaces: []string{"team-*"}}},
+				Deny:          []apisv1alpha1.PolicyRule{{Kinds: []string{"ClusterRole*"}}, {Kinds: []string{"Secret"}, Names: []string{"[admin"}}},
+				CELExpression: `metadata.name.startsWith(`,
+			},
+			wantFields: []string{"spec.policy.deny[1].names[0]", "spec.policy.celExpression"},
+		},
+		{
+			name:        "non-positive dry-run timeout",
+			credentials: apisv1alpha1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret, CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: secretRef("kubeconfig", "reachable")}},
+			admission:   apisv1alpha1.Admission{DryRunTimeout: &metav1.Duration{}},
+			wantFields:  []string{"spec.admission.dryRunTimeout"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &ProviderConfigValidator{Client: localCli, Probe: true}
+			pc := &apisv1alpha1.ProviderConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "remote"},
+				Spec:       apisv1alpha1.ProviderConfigSpec{Credentials: tt.credentials, Admission: tt.admission, Policy: tt.policy},
+			}
+
+			warnings, err := v.ValidateCreate(context.Background(), pc)
+			require.Equal(t, tt.wantWarnings, len(warnings) > 0, "warnings: %v", warnings)
+			if len(tt.wantFields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, apierrors.IsInvalid(err), "expected Invalid error, got %v", err)
+			var fields []string
+			for _, cause := range err.(apierrors.APIStatus).Status().Details.Causes { //nolint:forcetypeassert // checked by IsInvalid
+				fields = append(fields, cause.Field)
+			}
+			require.Equal(t, tt.wantFields, fields)
+
+			// the spec isn't validated again on metadata changes
+			_, err = v.ValidateUpdate(context.Background(), pc, pc)
+			require.NoError(t, err)
+		})
+	}
+}
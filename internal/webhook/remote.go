@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+	"aerf.io/provider-k8s/internal/objectindex"
+	"aerf.io/provider-k8s/internal/policy"
+	"aerf.io/provider-k8s/internal/restcfgutil"
+)
+
+// validateRemote checks the desired object against the Object's ProviderConfig and its remote cluster: it must be
+// allowed by the ProviderConfig's policy, must not be managed by another Object already, and it's dry-run applied if
+// the ProviderConfig opts in.
+// Given the old Object, the policy is checked only if the manifest or the ProviderConfig changed, and the uniqueness
+// only if the Object targets another remote object than before, so that the Objects denied by a newer policy or already
+// in conflict stay updatable. The dry-run is skipped as well if neither changed, e.g. on updates of finalizers.
+func (v *ObjectValidator) validateRemote(ctx context.Context, o, old *objv1alpha1.Object, desired *unstructured.Unstructured) (admission.Warnings, error) {
+	ref := o.GetProviderConfigReference()
+	if ref == nil {
+		return nil, nil
+	}
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := v.Client.Get(ctx, types.NamespacedName{Name: ref.Name}, pc); err != nil {
+		return skippedRemoteChecks(errors.Wrapf(err, "cannot get ProviderConfig %q", ref.Name)), nil
+	}
+	if !sameManifest(old, o) {
+		if err := policy.Check(pc.Spec.Policy, desired, v.CELLimits); err != nil {
+			return nil, apierrors.NewInvalid(objectGroupKind, o.GetName(), field.ErrorList{field.Forbidden(manifestPath, err.Error())})
+		}
+	}
+	rc, err := restcfgutil.RestConfigFromProviderConfig(ctx, pc, v.Client)
+	if err != nil {
+		return skippedRemoteChecks(err), nil
+	}
+
+	var warnings admission.Warnings
+	if !sameRemoteObject(old, o, desired) {
+		owner, err := objectindex.Owner(ctx, v.Client, o, rc.Host, desired)
+		switch {
+		case err != nil:
+			warnings = append(warnings, skippedRemoteChecks(err)...)
+		case owner != "":
+			return nil, apierrors.NewInvalid(objectGroupKind, o.GetName(), field.ErrorList{
+				field.Forbidden(manifestPath, "the remote object is already managed by "+owner),
+			})
+		}
+	}
+
+	if !pc.Spec.Admission.DryRun || sameManifest(old, o) {
+		return warnings, nil
+	}
+	dryRunWarnings, err := v.dryRun(ctx, o, pc, rc, desired)
+	return append(warnings, dryRunWarnings...), err
+}
+
+// sameManifest tells whether the old Object has the same manifest and ProviderConfig as the new one.
+func sameManifest(old, o *objv1alpha1.Object) bool {
+	if old == nil {
+		return false
+	}
+	oldRef, ref := old.GetProviderConfigReference(), o.GetProviderConfigReference()
+	if oldRef == nil || ref == nil || oldRef.Name != ref.Name {
+		return false
+	}
+	return bytes.Equal(old.Spec.ForProvider.Manifest.Raw, o.Spec.ForProvider.Manifest.Raw)
+}
+
+// sameRemoteObject tells whether the old Object targets the same remote object as the new one, whose desired object
+// is given.
+func sameRemoteObject(old, o *objv1alpha1.Object, desired *unstructured.Unstructured) bool {
+	if old == nil {
+		return false
+	}
+	oldRef, ref := old.GetProviderConfigReference(), o.GetProviderConfigReference()
+	if oldRef == nil || ref == nil || oldRef.Name != ref.Name {
+		return false
+	}
+	oldDesired, err := old.GetDesired()
+	if err != nil {
+		return false
+	}
+	return objectindex.Key("", oldDesired) == objectindex.Key("", desired)
+}
+
+func skippedRemoteChecks(err error) admission.Warnings {
+	return admission.Warnings{"Skipped the checks against the remote cluster: " + err.Error()}
+}
@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	objv1alpha1 "aerf.io/provider-k8s/apis/object/v1alpha1"
+	apisv1alpha1 "aerf.io/provider-k8s/apis/v1alpha1"
+	"aerf.io/provider-k8s/internal/objectindex"
+)
+
+func TestObjectValidatorUniqueness(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, apisv1alpha1.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, objv1alpha1.SchemeBuilder.AddToScheme(scheme))
+
+	providerConfig := &apisv1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "remote"},
+		Spec: apisv1alpha1.ProviderConfigSpec{
+			Credentials: apisv1alpha1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Name: "kubeconfig", Namespace: "crossplane-system"},
+						Key:             "kubeconfig",
+					},
+				},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "crossplane-system"},
+		Data:       map[string][]byte{"kubeconfig": []byte(kubeConfigFixture)},
+	}
+	object := func(name, manifest string) *objv1alpha1.Object {
+		o := &objv1alpha1.Object{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: objv1alpha1.ObjectSpec{
+				ForProvider: objv1alpha1.ObjectParameters{Manifest: runtime.RawExtension{Raw: []byte(manifest)}},
+			},
+		}
+		o.SetProviderConfigReference(&xpv1.Reference{Name: providerConfig.Name})
+		return o
+	}
+	const (
+		cm      = `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"default"}}`
+		otherCM = `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"other","namespace":"default"}}`
+	)
+	owner := object("owner", cm)
+	owner.CreationTimestamp = metav1.NewTime(time.Now())
+	owner.Status.AtProvider.Host = "https://remote.example.com"
+
+	localCli := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(providerConfig, secret, owner).
+		WithIndex(&objv1alpha1.Object{}, objectindex.Field, objectindex.IndexObject).
+		WithIndex(&objv1alpha1.ObjectSet{}, objectindex.Field, objectindex.IndexObjectSet).
+		Build()
+	v := &ObjectValidator{Client: localCli}
+
+	tests := []struct {
+		name    string
+		old     *objv1alpha1.Object
+		o       *objv1alpha1.Object
+		wantErr bool
+	}{
+		{
+			name:    "create targeting a managed remote object",
+			o:       object("duplicate", cm),
+			wantErr: true,
+		},
+		{
+			name: "create targeting another remote object",
+			o:    object("duplicate", otherCM),
+		},
+		{
+			name: "update of the owner",
+			old:  owner,
+			o:    owner,
+		},
+		{
+			name:    "update targeting a managed remote object",
+			old:     object("duplicate", otherCM),
+			o:       object("duplicate", cm),
+			wantErr: true,
+		},
+		{
+			name: "update of an Object already in conflict",
+			old:  object("duplicate", cm),
+			o:    object("duplicate", cm),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.old == nil {
+				_, err = v.ValidateCreate(context.Background(), tt.o)
+			} else {
+				_, err = v.ValidateUpdate(context.Background(), tt.old, tt.o)
+			}
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, apierrors.IsInvalid(err), "expected Invalid error, got %v", err)
+			require.Contains(t, err.Error(), `already managed by Object "owner"`)
+		})
+	}
+}
+
+func TestObjectValidatorPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, apisv1alpha1.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, objv1alpha1.SchemeBuilder.AddToScheme(scheme))
+
+	providerConfig := &apisv1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "remote"},
+		Spec: apisv1alpha1.ProviderConfigSpec{
+			Credentials: apisv1alpha1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Name: "kubeconfig", Namespace: "crossplane-system"},
+						Key:             "kubeconfig",
+					},
+				},
+			},
+			Policy: apisv1alpha1.Policy{
+				Deny:          []apisv1alpha1.PolicyRule{{Namespaces: []string{"kube-system"}}},
+				CELExpression: `!has(spec.hostNetwork)`,
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "crossplane-system"},
+		Data:       map[string][]byte{"kubeconfig": []byte(kubeConfigFixture)},
+	}
+	object := func(manifest string) *objv1alpha1.Object {
+		o := &objv1alpha1.Object{
+			ObjectMeta: metav1.ObjectMeta{Name: "object"},
+			Spec: objv1alpha1.ObjectSpec{
+				ForProvider: objv1alpha1.ObjectParameters{Manifest: runtime.RawExtension{Raw: []byte(manifest)}},
+			},
+		}
+		o.SetProviderConfigReference(&xpv1.Reference{Name: providerConfig.Name})
+		return o
+	}
+	const (
+		// the CEL expression reads spec, which ConfigMaps don't have
+		allowed     = `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"default"}}`
+		denied      = `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"kube-system"}}`
+		deniedByCEL = `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod","namespace":"default"},"spec":{"hostNetwork":true}}`
+	)
+
+	localCli := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(providerConfig, secret).
+		WithIndex(&objv1alpha1.Object{}, objectindex.Field, objectindex.IndexObject).
+		WithIndex(&objv1alpha1.ObjectSet{}, objectindex.Field, objectindex.IndexObjectSet).
+		Build()
+	v := &ObjectValidator{Client: localCli}
+
+	tests := []struct {
+		name    string
+		old     *objv1alpha1.Object
+		o       *objv1alpha1.Object
+		wantErr bool
+	}{
+		{
+			name: "create of an allowed remote object",
+			o:    object(allowed),
+		},
+		{
+			name:    "create of a denied remote object",
+			o:       object(denied),
+			wantErr: true,
+		},
+		{
+			name:    "create of a remote object denied by the CEL expression",
+			o:       object(deniedByCEL),
+			wantErr: true,
+		},
+		{
+			name:    "update to a denied remote object",
+			old:     object(allowed),
+			o:       object(denied),
+			wantErr: true,
+		},
+		{
+			name: "update of an Object denied by a newer policy",
+			old:  object(denied),
+			o:    object(denied),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.old == nil {
+				_, err = v.ValidateCreate(context.Background(), tt.o)
+			} else {
+				_, err = v.ValidateUpdate(context.Background(), tt.old, tt.o)
+			}
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, apierrors.IsInvalid(err), "expected Invalid error, got %v", err)
+			require.Contains(t, err.Error(), "denied by the ProviderConfig's policy")
+		})
+	}
+}